@@ -110,6 +110,8 @@ func CtxLogger(ctx context.Context) hlog.Logger {
 }
 
 // Logger tries to get logger from the context, otherwise returns the default logger.
+// The context's logger logs the span of the context when we created it, so
+// after starting a span, use Logger(ctx).WithCtx(ctx) to log the new span.
 func Logger(ctx context.Context) hlog.Logger {
 	if l := CtxLogger(ctx); l != nil {
 		return l
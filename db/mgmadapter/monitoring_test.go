@@ -0,0 +1,45 @@
+package mgmadapter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kamva/hexa"
+	"github.com/kamva/hexa/hlog"
+	"github.com/kamva/hexa/hlog/hlogtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/event"
+)
+
+func TestLogMonitor(t *testing.T) {
+	l, logs := hlogtest.New(hlog.DebugLevel)
+	m := NewLogMonitor(l)
+	c := context.Background()
+
+	cmd, err := bson.Marshal(bson.M{"find": "users"})
+	require.NoError(t, err)
+	finished := event.CommandFinishedEvent{CommandName: "find", RequestID: 7, ConnectionID: "conn-1"}
+	m.Started(c, &event.CommandStartedEvent{Command: cmd, DatabaseName: "app", CommandName: "find", RequestID: 7, ConnectionID: "conn-1"})
+	m.Succeeded(c, &event.CommandSucceededEvent{CommandFinishedEvent: finished, Reply: cmd})
+	m.Failed(c, &event.CommandFailedEvent{CommandFinishedEvent: finished, Failure: "timeout"})
+
+	assert.Equal(t, []string{"MongoDB command started", "MongoDB command succeeded", "MongoDB command failed"}, logs.Messages())
+	assert.Equal(t, 3, logs.FilterLevel(hlog.DebugLevel).FilterField("request_id", "7").FilterField("command_name", "find").Len())
+	started := logs.FilterMessage("MongoDB command started").All()[0]
+	assert.True(t, started.Has("db", "app"))
+	assert.True(t, started.Has("connection_id", "conn-1"))
+	assert.Equal(t, 1, logs.FilterField("failure", "timeout").Len())
+}
+
+func TestLogMonitor_ContextLogger(t *testing.T) {
+	l, logs := hlogtest.New(hlog.DebugLevel)
+	ctxLogger, ctxLogs := hlogtest.New(hlog.DebugLevel)
+	m := NewLogMonitor(l)
+
+	c := hexa.WithLogger(context.Background(), ctxLogger)
+	m.Failed(c, &event.CommandFailedEvent{Failure: "timeout"})
+	assert.Equal(t, 0, logs.Len())
+	assert.Equal(t, 1, ctxLogs.Len(), "it must use the context's logger")
+}
@@ -13,6 +13,9 @@ type MutexOptions struct {
 	// If owner is empty, DLM uses default Owner.
 	Owner string
 	TTL   time.Duration
+	// KeepAlive renews the lock every TTL/3 after a successful lock
+	// until you unlock it. The mutex implements KeepAliveMutex.
+	KeepAlive bool
 }
 
 // DLM is distributed lock manager.
@@ -62,3 +65,86 @@ type Mutex interface {
 	// and do not return any error.
 	Unlock(ctx context.Context) error
 }
+
+// FencedMutex is a mutex which issues a fencing token on each lock. A
+// paused process may still act after its lock is expired, so storages
+// can reject its writes whose token is older than the last seen token.
+type FencedMutex interface {
+	Mutex
+
+	// Token returns the fencing token of the current lock, or zero if
+	// the mutex is not locked. Tokens of a key increase whenever a new
+	// owner acquires the lock, refreshes keep the token.
+	Token() int64
+}
+
+type SemaphoreOptions struct {
+	Key string
+	// If owner is empty, DLM uses default Owner.
+	Owner string
+	TTL   time.Duration
+	// Permits is the maximum number of owners that can hold the semaphore.
+	Permits int
+}
+
+// SemaphoreManager creates distributed counting semaphores. DLMs can
+// implement it.
+type SemaphoreManager interface {
+	NewSemaphore(o SemaphoreOptions) Semaphore
+}
+
+// Semaphore is a distributed counting semaphore. Each owner holds one
+// permit, it follows the Mutex rules: acquiring it again refreshes the
+// owner's permit, different semaphores with same key and same owner share
+// the permit, and the ttl begins at acquire time.
+type Semaphore interface {
+	// Acquire tries to acquire a permit or waits for a free permit.
+	Acquire(ctx context.Context) error
+	// TryAcquire tries to acquire a permit or returns the
+	// ErrLockAlreadyAcquired error if all permits are held.
+	TryAcquire(ctx context.Context) error
+	// Release releases the permit. It ignores a released permit.
+	Release(ctx context.Context) error
+}
+
+// RWMutexManager creates distributed read/write locks. DLMs can implement it.
+type RWMutexManager interface {
+	NewRWMutex(o MutexOptions) RWMutex
+}
+
+// RWMutex is a distributed read/write lock. Multiple owners can hold the
+// read lock, but the write lock is exclusive. Its Mutex methods are for
+// the write lock. An owner can take the write lock while it's the only
+// reader. Writers don't have priority, so a busy read lock can delay them.
+type RWMutex interface {
+	Mutex
+
+	// RLock tries to read lock or waits for the write lock to release.
+	RLock(ctx context.Context) error
+	// TryRLock tries to read lock or returns the
+	// ErrLockAlreadyAcquired error if it's write locked.
+	TryRLock(ctx context.Context) error
+	// RUnlock releases the read lock. It ignores a released lock.
+	RUnlock(ctx context.Context) error
+}
+
+// LockInfo describes a held mutex lock.
+type LockInfo struct {
+	Key    string    `json:"key"`
+	Owner  string    `json:"owner"`
+	Expiry time.Time `json:"expiry"`
+}
+
+// LockAdmin administrates mutex locks of a DLM, e.g., to find out which
+// instance holds a lock when a deploy goes wrong. DLMs can implement it.
+type LockAdmin interface {
+	// Locks returns the unexpired locks whose key begins with the prefix.
+	Locks(ctx context.Context, prefix string) ([]LockInfo, error)
+	// ForceUnlock releases the lock regardless of its owner and logs it
+	// for audit. It ignores a released lock.
+	ForceUnlock(ctx context.Context, key string) error
+	// ClearExpired removes the expired locks from the storage and returns
+	// the number of removed locks. Storages that remove expired locks by
+	// themselves return zero.
+	ClearExpired(ctx context.Context) (int64, error)
+}
@@ -0,0 +1,218 @@
+package hexa
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// KeepAliveMutex is a mutex which renews its lock in the background,
+// so you can hold it longer than its TTL.
+//
+// Example:
+//
+//	if err := m.Lock(ctx); err != nil {
+//		return err
+//	}
+//	defer m.Unlock(ctx)
+//	for _, job := range jobs {
+//		select {
+//		case <-m.Lost():
+//			return errors.New("lock is lost")
+//		default:
+//			run(job)
+//		}
+//	}
+type KeepAliveMutex interface {
+	Mutex
+
+	// Lost returns a channel which is closed as soon as we can't renew
+	// the current lock anymore, so you should stop your work before
+	// another instance takes over the lock. Call it after each lock,
+	// because each lock has its own channel.
+	Lost() <-chan struct{}
+}
+
+type keepAliveMutex struct {
+	Mutex
+	ttl time.Duration
+
+	// callMu serializes calls to the mutex. We never hold mu while we're
+	// holding it, so a blocking Lock doesn't block Lost and Token.
+	callMu sync.Mutex
+
+	// mu guards the fields below.
+	mu    sync.Mutex
+	token int64
+	lost  chan struct{}
+	stop  chan struct{} // is nil when we're not renewing the lock.
+	done  chan struct{} // is closed when the renewal goroutine returns.
+}
+
+// NewKeepAliveMutex returns a mutex which renews m's lock every ttl/3
+// after a successful lock until you unlock it. ttl must be the mutex's
+// TTL. It doesn't renew locks if ttl is zero. A failed renewal is
+// retried until the lock expires, unless another owner holds the lock.
+func NewKeepAliveMutex(m Mutex, ttl time.Duration) KeepAliveMutex {
+	return &keepAliveMutex{
+		Mutex: m,
+		ttl:   ttl,
+		lost:  make(chan struct{}),
+	}
+}
+
+func (m *keepAliveMutex) Lock(ctx context.Context) error {
+	return m.lock(func() error { return m.Mutex.Lock(ctx) })
+}
+
+func (m *keepAliveMutex) TryLock(ctx context.Context) error {
+	return m.lock(func() error { return m.Mutex.TryLock(ctx) })
+}
+
+// lock locks the mutex using fn and then starts renewal of the lock.
+func (m *keepAliveMutex) lock(fn func() error) error {
+	token, err := m.call(fn)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.token = token
+	if err != nil {
+		return err
+	}
+	m.keepAlive()
+	return nil
+}
+
+// call calls fn on the mutex and returns the mutex's fencing token.
+func (m *keepAliveMutex) call(fn func() error) (int64, error) {
+	m.callMu.Lock()
+	defer m.callMu.Unlock()
+	err := fn()
+	if fm, ok := m.Mutex.(FencedMutex); ok {
+		return fm.Token(), err
+	}
+	return 0, err
+}
+
+func (m *keepAliveMutex) Unlock(ctx context.Context) error {
+	m.mu.Lock()
+	stop, done := m.stop, m.done
+	m.stop = nil
+	m.mu.Unlock()
+
+	if stop != nil {
+		close(stop)
+		<-done
+	}
+
+	token, err := m.call(func() error { return m.Mutex.Unlock(ctx) })
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.token = token
+	return err
+}
+
+// Token returns the fencing token of the mutex if it's a FencedMutex,
+// otherwise zero.
+func (m *keepAliveMutex) Token() int64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.token
+}
+
+func (m *keepAliveMutex) Lost() <-chan struct{} {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.lost
+}
+
+// keepAlive starts renewal of the lock if we're not renewing it.
+// The caller must hold the lock of mu.
+func (m *keepAliveMutex) keepAlive() {
+	if m.stop != nil || m.ttl <= 0 {
+		return
+	}
+
+	select {
+	case <-m.lost: // the previous lock is lost, so this lock needs a new channel.
+		m.lost = make(chan struct{})
+	default:
+	}
+
+	m.stop = make(chan struct{})
+	m.done = make(chan struct{})
+	go m.renew(m.stop, m.done, m.lost)
+}
+
+// renew renews the lock every ttl/3. If a renewal fails, it retries it
+// until the lock expires, then it reports the lock as lost. It reports
+// the lock as lost right away if another owner holds the lock.
+func (m *keepAliveMutex) renew(stop chan struct{}, done chan struct{}, lost chan struct{}) {
+	defer close(done)
+	interval := m.ttl / 3
+	retryInterval := m.ttl / 10
+	t := time.NewTimer(interval)
+	defer t.Stop()
+
+	renewed := time.Now()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-t.C:
+		}
+
+		start := time.Now()
+		err := m.renewOnce(stop, m.ttl-start.Sub(renewed))
+		switch {
+		case errors.Is(err, errRenewalStopped):
+			return
+		case err == nil:
+			renewed = start
+			t.Reset(interval)
+		case errors.Is(err, ErrLockAlreadyAcquired) || time.Since(renewed)+retryInterval >= m.ttl:
+			m.markLost(stop, lost)
+			return
+		default:
+			t.Reset(retryInterval)
+		}
+	}
+}
+
+// errRenewalStopped is returned by renewOnce when the mutex is unlocking.
+var errRenewalStopped = errors.New("renewal is stopped")
+
+// renewOnce renews the lock within the timeout.
+func (m *keepAliveMutex) renewOnce(stop chan struct{}, timeout time.Duration) error {
+	select {
+	case <-stop: // the mutex is unlocking.
+		return errRenewalStopped
+	default:
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	token, err := m.call(func() error { return m.Mutex.TryLock(ctx) })
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.stop != stop { // the mutex is unlocked while we were renewing it.
+		return errRenewalStopped
+	}
+	m.token = token
+	return err
+}
+
+// markLost closes the lost channel and stops the renewal.
+func (m *keepAliveMutex) markLost(stop chan struct{}, lost chan struct{}) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.stop != stop { // the mutex is unlocking.
+		return
+	}
+	m.stop = nil
+	close(lost)
+}
+
+var _ KeepAliveMutex = &keepAliveMutex{}
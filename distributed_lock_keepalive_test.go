@@ -0,0 +1,180 @@
+package hexa
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeMutex counts locks and fails them after fail is set. It fails
+// the next `transient` locks with a transient error.
+type fakeMutex struct {
+	mu        sync.Mutex
+	locks     int
+	unlocked  bool
+	fail      bool
+	transient int
+	// Lock blocks until block is closed if it's not nil.
+	block chan struct{}
+}
+
+func (m *fakeMutex) Lock(ctx context.Context) error {
+	if m.block != nil {
+		<-m.block
+	}
+	return m.TryLock(ctx)
+}
+
+func (m *fakeMutex) TryLock(context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.fail {
+		return ErrLockAlreadyAcquired
+	}
+	if m.transient > 0 {
+		m.transient--
+		return errors.New("connection reset")
+	}
+	m.locks++
+	m.unlocked = false
+	return nil
+}
+
+func (m *fakeMutex) Unlock(context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.unlocked = true
+	return nil
+}
+
+func (m *fakeMutex) lockCount() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.locks
+}
+
+func (m *fakeMutex) setFail(fail bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.fail = fail
+}
+
+func (m *fakeMutex) setTransient(n int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.transient = n
+}
+
+func TestKeepAliveMutex_RenewsUntilUnlock(t *testing.T) {
+	ctx := context.Background()
+	fm := &fakeMutex{}
+	m := NewKeepAliveMutex(fm, 30*time.Millisecond)
+
+	require.NoError(t, m.Lock(ctx))
+	require.Eventually(t, func() bool { return fm.lockCount() >= 3 }, time.Second, time.Millisecond)
+
+	require.NoError(t, m.Unlock(ctx))
+	count := fm.lockCount()
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, count, fm.lockCount(), "unlock must stop the renewal")
+	assert.True(t, fm.unlocked)
+
+	select {
+	case <-m.Lost():
+		t.Fatal("unlock must not report the lock as lost")
+	default:
+	}
+}
+
+func TestKeepAliveMutex_Lost(t *testing.T) {
+	ctx := context.Background()
+	fm := &fakeMutex{}
+	m := NewKeepAliveMutex(fm, 30*time.Millisecond)
+
+	require.NoError(t, m.TryLock(ctx))
+	lost := m.Lost()
+	fm.setFail(true)
+
+	select {
+	case <-lost:
+	case <-time.After(time.Second):
+		t.Fatal("failed renewal must close the lost channel")
+	}
+
+	// A new lock has a new channel.
+	fm.setFail(false)
+	require.NoError(t, m.TryLock(ctx))
+	select {
+	case <-m.Lost():
+		t.Fatal("the new lock must not be lost")
+	default:
+	}
+	require.NoError(t, m.Unlock(ctx))
+}
+
+func TestKeepAliveMutex_FailedLockDoesNotRenew(t *testing.T) {
+	fm := &fakeMutex{fail: true}
+	m := NewKeepAliveMutex(fm, 30*time.Millisecond)
+
+	assert.ErrorIs(t, m.TryLock(context.Background()), ErrLockAlreadyAcquired)
+	time.Sleep(50 * time.Millisecond)
+	assert.Zero(t, fm.lockCount())
+}
+
+func TestKeepAliveMutex_RetriesTransientRenewalErrors(t *testing.T) {
+	ctx := context.Background()
+	fm := &fakeMutex{}
+	m := NewKeepAliveMutex(fm, 90*time.Millisecond)
+
+	require.NoError(t, m.TryLock(ctx))
+	fm.setTransient(2)
+	require.Eventually(t, func() bool { return fm.lockCount() >= 3 }, time.Second, time.Millisecond)
+	select {
+	case <-m.Lost():
+		t.Fatal("a transient renewal error must be retried")
+	default:
+	}
+	require.NoError(t, m.Unlock(ctx))
+}
+
+func TestKeepAliveMutex_LostAfterTTLOfTransientErrors(t *testing.T) {
+	ctx := context.Background()
+	fm := &fakeMutex{}
+	m := NewKeepAliveMutex(fm, 30*time.Millisecond)
+
+	require.NoError(t, m.TryLock(ctx))
+	fm.setTransient(1000)
+	select {
+	case <-m.Lost():
+	case <-time.After(time.Second):
+		t.Fatal("renewal errors within the TTL must close the lost channel")
+	}
+}
+
+func TestKeepAliveMutex_BlockingLockDoesNotBlockLost(t *testing.T) {
+	fm := &fakeMutex{block: make(chan struct{})}
+	m := NewKeepAliveMutex(fm, 30*time.Millisecond)
+
+	locked := make(chan error)
+	go func() { locked <- m.Lock(context.Background()) }()
+
+	got := make(chan struct{})
+	go func() {
+		m.Lost()
+		close(got)
+	}()
+	select {
+	case <-got:
+	case <-time.After(time.Second):
+		t.Fatal("Lost must not wait for a blocking Lock")
+	}
+
+	close(fm.block)
+	require.NoError(t, <-locked)
+	require.NoError(t, m.Unlock(context.Background()))
+}
@@ -5,6 +5,8 @@ import (
 	"net/http"
 	"testing"
 
+	"github.com/kamva/hexa/hlog"
+	"github.com/kamva/hexa/hlog/hlogtest"
 	"github.com/kamva/tracer"
 	"github.com/stretchr/testify/assert"
 )
@@ -35,3 +37,24 @@ func TestDefaultError_Unwrap(t *testing.T) {
 	// A non-matching target stays false.
 	assert.False(t, errors.Is(err, errors.New("other")))
 }
+
+func TestDefaultError_ReportIfNeeded(t *testing.T) {
+	l, logs := hlogtest.New(hlog.DebugLevel)
+
+	err := NewError(http.StatusBadRequest, "lib.bad_request")
+	assert.False(t, err.ReportIfNeeded(l, nil))
+	assert.Zero(t, logs.Len())
+
+	err = NewError(http.StatusInternalServerError, "lib.internal").
+		SetError(errors.New("db is down")).
+		SetReportData(Map{"query": "q"})
+	assert.True(t, err.ReportIfNeeded(l.With(hlog.String("_user_id", "1")), nil))
+
+	reported := logs.FilterLevel(hlog.ErrorLevel).FilterField("_error_id", "lib.internal").All()
+	if assert.Len(t, reported, 1) {
+		assert.Equal(t, err.Error(), reported[0].Message)
+		assert.True(t, reported[0].Has("_http_status", int64(http.StatusInternalServerError)))
+		assert.True(t, reported[0].Has("query", "q"))
+		assert.True(t, reported[0].Has("_user_id", "1"))
+	}
+}
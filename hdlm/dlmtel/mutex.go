@@ -0,0 +1,191 @@
+package dlmtel
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/kamva/hexa"
+	"github.com/kamva/hexa/hlog"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// mutex records telemetry of a mutex.
+type mutex struct {
+	hexa.Mutex
+	t   *Telemetry
+	key string
+	ttl time.Duration
+
+	// mu guards the fields below.
+	mu sync.Mutex
+	// acquired is when we acquired the current lock, it's zero if we
+	// don't hold the lock.
+	acquired time.Time
+	// refreshed is when we acquired or refreshed the current lock.
+	refreshed time.Time
+}
+
+// Lock tries the lock once before waiting for it, so we can see
+// its contention.
+func (m *mutex) Lock(c context.Context) error {
+	return m.acquire(c, "lock", func(c context.Context) error {
+		if m.isHeld() {
+			return m.Mutex.Lock(c)
+		}
+
+		err := m.Mutex.TryLock(c)
+		if !errors.Is(err, hexa.ErrLockAlreadyAcquired) {
+			return err
+		}
+		err = m.Mutex.Lock(c)
+		// acquire counts the contention if we couldn't acquire the lock
+		// at all, e.g., when the wait timed out.
+		if !errors.Is(err, hexa.ErrLockAlreadyAcquired) {
+			m.t.contentions.Add(c, 1, m.t.attrs(m.key, OperationKey.String("lock"))...)
+		}
+		return err
+	})
+}
+
+func (m *mutex) TryLock(c context.Context) error {
+	return m.acquire(c, "try_lock", m.Mutex.TryLock)
+}
+
+func (m *mutex) Unlock(c context.Context) error {
+	c, span := m.t.tracer.Start(c, "dlm.Unlock", trace.WithAttributes(KeyKey.String(m.key)))
+	defer span.End()
+
+	err := m.Mutex.Unlock(c)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+	m.release(c, time.Now())
+	return nil
+}
+
+func (m *mutex) isHeld() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return !m.acquired.IsZero()
+}
+
+// acquire locks or refreshes the lock using fn and records its telemetry.
+func (m *mutex) acquire(c context.Context, op string, fn func(c context.Context) error) error {
+	c, span := m.t.tracer.Start(c, "dlm."+op, trace.WithAttributes(KeyKey.String(m.key)))
+	defer span.End()
+
+	held := m.isHeld()
+	start := time.Now()
+	err := fn(c)
+	now := time.Now()
+	wait := now.Sub(start)
+
+	res := result(held, err)
+	span.SetAttributes(ResultKey.String(res))
+	if err != nil && res != ResultContended {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	attrs := m.t.attrs(m.key, OperationKey.String(op), ResultKey.String(res))
+
+	if held {
+		if err != nil {
+			m.t.refreshFailures.Add(c, 1, attrs...)
+			m.release(c, now) // we lost the lock.
+			return err
+		}
+		m.refresh(c, now)
+		return nil
+	}
+
+	m.t.waitTime.Record(c, float64(wait)/float64(time.Millisecond), attrs...)
+	if res == ResultContended {
+		m.t.contentions.Add(c, 1, attrs...)
+	}
+	if err != nil {
+		return err
+	}
+
+	if m.t.o.SlowAcquire > 0 && wait >= m.t.o.SlowAcquire {
+		hexa.Logger(c).Warn("slow lock acquisition", hlog.String("key", m.key), hlog.String("operation", op), hlog.Duration("wait", wait))
+	}
+	m.mu.Lock()
+	m.acquired, m.refreshed = now, now
+	m.mu.Unlock()
+	return nil
+}
+
+// refresh records a refresh of the lock at the time.
+func (m *mutex) refresh(c context.Context, at time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.checkExpiry(c, at)
+	m.refreshed = at
+}
+
+// release records the end of holding the lock at the time.
+func (m *mutex) release(c context.Context, at time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.acquired.IsZero() {
+		return
+	}
+
+	m.checkExpiry(c, at)
+	m.t.holdTime.Record(c, float64(at.Sub(m.acquired))/float64(time.Millisecond), m.t.attrs(m.key)...)
+	m.acquired, m.refreshed = time.Time{}, time.Time{}
+}
+
+// checkExpiry records an expiry if the lock expired before the time.
+// The caller must hold the lock of mu.
+func (m *mutex) checkExpiry(c context.Context, at time.Time) {
+	if m.ttl > 0 && at.Sub(m.refreshed) > m.ttl {
+		m.t.expirations.Add(c, 1, m.t.attrs(m.key)...)
+	}
+}
+
+func result(held bool, err error) string {
+	switch {
+	case err == nil && held:
+		return ResultRefreshed
+	case err == nil:
+		return ResultAcquired
+	case errors.Is(err, hexa.ErrLockAlreadyAcquired):
+		return ResultContended
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return ResultCanceled
+	}
+	return ResultError
+}
+
+type fencedMutex struct {
+	*mutex
+}
+
+func (m *fencedMutex) Token() int64 {
+	return m.Mutex.(hexa.FencedMutex).Token()
+}
+
+type keepAliveMutex struct {
+	*mutex
+}
+
+func (m *keepAliveMutex) Token() int64 {
+	if fm, ok := m.Mutex.(hexa.FencedMutex); ok {
+		return fm.Token()
+	}
+	return 0
+}
+
+func (m *keepAliveMutex) Lost() <-chan struct{} {
+	return m.Mutex.(hexa.KeepAliveMutex).Lost()
+}
+
+var _ hexa.Mutex = &mutex{}
+var _ hexa.FencedMutex = &fencedMutex{}
+var _ hexa.KeepAliveMutex = &keepAliveMutex{}
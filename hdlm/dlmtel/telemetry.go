@@ -0,0 +1,157 @@
+// Package dlmtel decorates hexa DLMs and mutexes to record OpenTelemetry
+// spans and metrics of locks: wait time, hold time, contention, refresh
+// failures and TTL expiries. It logs slow acquisitions too.
+package dlmtel
+
+import (
+	"time"
+
+	"github.com/kamva/hexa"
+	"github.com/kamva/tracer"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/global"
+	"go.opentelemetry.io/otel/metric/unit"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/kamva/hexa/hdlm/dlmtel"
+
+// Attribute keys of spans and metrics.
+const (
+	KeyKey       = attribute.Key("dlm.key")
+	OperationKey = attribute.Key("dlm.operation")
+	ResultKey    = attribute.Key("dlm.result")
+)
+
+// Results of lock operations.
+const (
+	ResultAcquired  = "acquired"
+	ResultRefreshed = "refreshed"
+	ResultContended = "contended"
+	ResultCanceled  = "canceled"
+	ResultError     = "error"
+)
+
+type Options struct {
+	// TracerProvider default value is the global tracer provider.
+	TracerProvider trace.TracerProvider
+	// MeterProvider default value is the global meter provider.
+	MeterProvider metric.MeterProvider
+	// SlowAcquire is the wait time after which an acquisition is slow,
+	// so it logs it using hexa.Logger(ctx). zero disables it.
+	SlowAcquire time.Duration
+	// KeyAttribute adds the lock key to metrics. Enable it just if your
+	// lock keys have a low cardinality. Spans always have the key.
+	KeyAttribute bool
+	// DefaultTTL is the TTL of the decorated DLM's NewMutex, so we can
+	// detect TTL expiries of its mutexes. Other mutexes use the TTL of
+	// NewMutexWithTTL and MutexOptions, so it's optional if you don't
+	// use NewMutex.
+	DefaultTTL time.Duration
+}
+
+// Telemetry decorates DLMs and mutexes.
+type Telemetry struct {
+	o      Options
+	tracer trace.Tracer
+
+	waitTime        metric.Float64Histogram
+	holdTime        metric.Float64Histogram
+	contentions     metric.Int64Counter
+	refreshFailures metric.Int64Counter
+	expirations     metric.Int64Counter
+}
+
+func New(o Options) (*Telemetry, error) {
+	if o.TracerProvider == nil {
+		o.TracerProvider = otel.GetTracerProvider()
+	}
+	if o.MeterProvider == nil {
+		o.MeterProvider = global.GetMeterProvider()
+	}
+
+	t := &Telemetry{o: o, tracer: o.TracerProvider.Tracer(instrumentationName)}
+	meter := o.MeterProvider.Meter(instrumentationName)
+
+	var err error
+	if t.waitTime, err = meter.NewFloat64Histogram("dlm.lock.wait_time",
+		metric.WithDescription("time to acquire locks"), metric.WithUnit(unit.Milliseconds)); err != nil {
+		return nil, tracer.Trace(err)
+	}
+	if t.holdTime, err = meter.NewFloat64Histogram("dlm.lock.hold_time",
+		metric.WithDescription("time between acquiring and releasing locks"), metric.WithUnit(unit.Milliseconds)); err != nil {
+		return nil, tracer.Trace(err)
+	}
+	if t.contentions, err = meter.NewInt64Counter("dlm.lock.contentions",
+		metric.WithDescription("acquisitions that found the lock held by another owner")); err != nil {
+		return nil, tracer.Trace(err)
+	}
+	if t.refreshFailures, err = meter.NewInt64Counter("dlm.lock.refresh_failures",
+		metric.WithDescription("failed refreshes of held locks")); err != nil {
+		return nil, tracer.Trace(err)
+	}
+	if t.expirations, err = meter.NewInt64Counter("dlm.lock.expirations",
+		metric.WithDescription("held locks that expired before their refresh or release")); err != nil {
+		return nil, tracer.Trace(err)
+	}
+	return t, nil
+}
+
+// DLM returns a DLM whose mutexes record telemetry. Please note it's
+// just a hexa.DLM, so use the decorated DLM for its other interfaces,
+// e.g., its health.
+func (t *Telemetry) DLM(d hexa.DLM) hexa.DLM {
+	return &dlm{DLM: d, t: t}
+}
+
+// Mutex returns a mutex which records telemetry of m. key and ttl must
+// be the mutex's key and TTL. It keeps the FencedMutex and KeepAliveMutex
+// interfaces of m.
+func (t *Telemetry) Mutex(m hexa.Mutex, key string, ttl time.Duration) hexa.Mutex {
+	mu := &mutex{Mutex: m, t: t, key: key, ttl: ttl}
+	switch m.(type) {
+	case hexa.KeepAliveMutex:
+		return &keepAliveMutex{mu}
+	case hexa.FencedMutex:
+		return &fencedMutex{mu}
+	}
+	return mu
+}
+
+// attrs returns the metric attributes.
+func (t *Telemetry) attrs(key string, kv ...attribute.KeyValue) []attribute.KeyValue {
+	if t.o.KeyAttribute {
+		kv = append(kv, KeyKey.String(key))
+	}
+	return kv
+}
+
+type dlm struct {
+	hexa.DLM
+	t *Telemetry
+}
+
+func (d *dlm) NewMutex(key string) hexa.Mutex {
+	return d.t.Mutex(d.DLM.NewMutex(key), key, d.t.o.DefaultTTL)
+}
+
+func (d *dlm) NewMutexWithTTL(key string, ttl time.Duration) hexa.Mutex {
+	return d.t.Mutex(d.DLM.NewMutexWithTTL(key, ttl), key, ttl)
+}
+
+// NewMutexWithOptions decorates the mutex under the keep-alive, so we see
+// its renewals and record their failures.
+func (d *dlm) NewMutexWithOptions(o hexa.MutexOptions) hexa.Mutex {
+	keepAlive := o.KeepAlive
+	o.KeepAlive = false
+
+	m := d.t.Mutex(d.DLM.NewMutexWithOptions(o), o.Key, o.TTL)
+	if keepAlive {
+		return hexa.NewKeepAliveMutex(m, o.TTL)
+	}
+	return m
+}
+
+var _ hexa.DLM = &dlm{}
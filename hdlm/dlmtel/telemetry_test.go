@@ -0,0 +1,158 @@
+package dlmtel
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kamva/hexa"
+	"github.com/kamva/hexa/hdlm/dlmtest"
+	"github.com/kamva/hexa/hdlm/memlock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/metric/metrictest"
+	tracesdk "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+type recorder struct {
+	spans   *tracetest.SpanRecorder
+	metrics *metrictest.MeterProvider
+}
+
+func newTelemetry(t *testing.T, o Options) (*Telemetry, *recorder) {
+	r := &recorder{spans: tracetest.NewSpanRecorder(), metrics: metrictest.NewMeterProvider()}
+	o.TracerProvider = tracesdk.NewTracerProvider(tracesdk.WithSpanProcessor(r.spans))
+	o.MeterProvider = r.metrics
+
+	tel, err := New(o)
+	require.NoError(t, err)
+	return tel, r
+}
+
+// sum returns the sum of the counter's measurements.
+func (r *recorder) sum(name string) int64 {
+	var n int64
+	for _, m := range metrictest.AsStructs(r.metrics.MeasurementBatches) {
+		if m.Name == name {
+			n += m.Number.AsInt64()
+		}
+	}
+	return n
+}
+
+// records returns the number of the histogram's measurements.
+func (r *recorder) records(name string) int {
+	var n int
+	for _, m := range metrictest.AsStructs(r.metrics.MeasurementBatches) {
+		if m.Name == name {
+			n++
+		}
+	}
+	return n
+}
+
+func (r *recorder) spanNames() []string {
+	var names []string
+	for _, s := range r.spans.Ended() {
+		names = append(names, s.Name())
+	}
+	return names
+}
+
+func newDLM(t *testing.T, o Options) (hexa.DLM, *recorder) {
+	d, err := memlock.NewDlm(memlock.DlmOptions{DefaultTTL: time.Minute})
+	require.NoError(t, err)
+	tel, r := newTelemetry(t, o)
+	return tel.DLM(d), r
+}
+
+func TestConformance(t *testing.T) {
+	dlmtest.Run(t, func(t *testing.T) hexa.DLM {
+		d, _ := newDLM(t, Options{})
+		return d
+	})
+}
+
+func TestMutex_Telemetry(t *testing.T) {
+	ctx := context.Background()
+	d, r := newDLM(t, Options{})
+	m1 := d.NewMutexWithOptions(hexa.MutexOptions{Key: "k", Owner: "a", TTL: time.Minute})
+	m2 := d.NewMutexWithOptions(hexa.MutexOptions{Key: "k", Owner: "b", TTL: time.Minute})
+
+	require.NoError(t, m1.Lock(ctx))
+	require.NoError(t, m1.TryLock(ctx)) // refresh.
+	assert.ErrorIs(t, m2.TryLock(ctx), hexa.ErrLockAlreadyAcquired)
+
+	locked := make(chan error)
+	go func() { locked <- m2.Lock(ctx) }()
+	time.Sleep(50 * time.Millisecond)
+	require.NoError(t, m1.Unlock(ctx))
+	require.NoError(t, <-locked)
+	require.NoError(t, m2.Unlock(ctx))
+
+	assert.Equal(t, int64(2), r.sum("dlm.lock.contentions"))
+	assert.Equal(t, 3, r.records("dlm.lock.wait_time"))
+	assert.Equal(t, 2, r.records("dlm.lock.hold_time"))
+	assert.Zero(t, r.sum("dlm.lock.refresh_failures"))
+	assert.Zero(t, r.sum("dlm.lock.expirations"))
+	assert.Equal(t, []string{"dlm.lock", "dlm.try_lock", "dlm.try_lock", "dlm.Unlock", "dlm.lock", "dlm.Unlock"}, r.spanNames())
+}
+
+func TestMutex_RefreshFailureAndExpiry(t *testing.T) {
+	ctx := context.Background()
+	d, r := newDLM(t, Options{})
+	m1 := d.NewMutexWithOptions(hexa.MutexOptions{Key: "k", Owner: "a", TTL: 20 * time.Millisecond})
+	m2 := d.NewMutexWithOptions(hexa.MutexOptions{Key: "k", Owner: "b", TTL: time.Minute})
+
+	require.NoError(t, m1.TryLock(ctx))
+	time.Sleep(30 * time.Millisecond)
+	require.NoError(t, m2.TryLock(ctx)) // takes over the expired lock.
+
+	assert.ErrorIs(t, m1.TryLock(ctx), hexa.ErrLockAlreadyAcquired)
+	assert.Equal(t, int64(1), r.sum("dlm.lock.refresh_failures"))
+	assert.Equal(t, int64(1), r.sum("dlm.lock.expirations"))
+	assert.Equal(t, 1, r.records("dlm.lock.hold_time"))
+}
+
+func TestMutex_KeepAlive(t *testing.T) {
+	ctx := context.Background()
+	d, r := newDLM(t, Options{})
+	m := d.NewMutexWithOptions(hexa.MutexOptions{Key: "k", Owner: "a", TTL: 30 * time.Millisecond, KeepAlive: true})
+	require.Implements(t, (*hexa.KeepAliveMutex)(nil), m)
+	require.Implements(t, (*hexa.FencedMutex)(nil), m)
+
+	require.NoError(t, m.Lock(ctx))
+	time.Sleep(100 * time.Millisecond)
+	require.NoError(t, m.Unlock(ctx))
+
+	assert.Greater(t, len(r.spanNames()), 3, "renewals must be recorded")
+	assert.Zero(t, r.sum("dlm.lock.expirations"))
+}
+
+func TestTelemetry_Mutex(t *testing.T) {
+	d, err := memlock.NewDlm(memlock.DlmOptions{})
+	require.NoError(t, err)
+	tel, _ := newTelemetry(t, Options{})
+
+	opts := hexa.MutexOptions{Key: "k", TTL: time.Minute}
+	assert.Implements(t, (*hexa.FencedMutex)(nil), tel.Mutex(d.NewMutexWithOptions(opts), "k", time.Minute))
+	opts.KeepAlive = true
+	assert.Implements(t, (*hexa.KeepAliveMutex)(nil), tel.Mutex(d.NewMutexWithOptions(opts), "k", time.Minute))
+}
+
+// heldMutex is a mutex which is always held by another owner, like a
+// mutex whose Lock reached its MaxWait.
+type heldMutex struct{}
+
+func (heldMutex) Lock(context.Context) error    { return hexa.ErrLockAlreadyAcquired }
+func (heldMutex) TryLock(context.Context) error { return hexa.ErrLockAlreadyAcquired }
+func (heldMutex) Unlock(context.Context) error  { return nil }
+
+func TestMutex_LockContentionCountedOnce(t *testing.T) {
+	tel, r := newTelemetry(t, Options{})
+	m := tel.Mutex(heldMutex{}, "k", time.Minute)
+
+	assert.ErrorIs(t, m.Lock(context.Background()), hexa.ErrLockAlreadyAcquired)
+	assert.Equal(t, int64(1), r.sum("dlm.lock.contentions"))
+}
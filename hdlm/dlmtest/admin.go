@@ -0,0 +1,99 @@
+package dlmtest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kamva/hexa"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Admin is a DLM which administrates its locks.
+type Admin interface {
+	hexa.DLM
+	hexa.LockAdmin
+}
+
+// AdminFactory returns a new DLM which administrates its locks.
+type AdminFactory func(t *testing.T) Admin
+
+// RunLockAdmin runs the conformance suite against the lock admin of the
+// DLMs returned by newAdmin.
+func RunLockAdmin(t *testing.T, newAdmin AdminFactory) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, a Admin)
+	}{
+		{"Locks", testAdminLocks},
+		{"ForceUnlock", testAdminForceUnlock},
+		{"ClearExpired", testAdminClearExpired},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) { tt.fn(t, newAdmin(t)) })
+	}
+}
+
+func testAdminLocks(t *testing.T, a Admin) {
+	ctx := context.Background()
+	k := key(t)
+	before := time.Now()
+	require.NoError(t, mutex(a, k+"-b", "owner-2", 0).TryLock(ctx))
+	require.NoError(t, mutex(a, k+"-a", "owner-1", 0).TryLock(ctx))
+	require.NoError(t, mutex(a, k+"-expired", "owner-1", TTL).TryLock(ctx))
+	require.NoError(t, mutex(a, "other-"+k, "owner-1", 0).TryLock(ctx))
+	time.Sleep(TTL + TTL/2)
+
+	locks, err := a.Locks(ctx, k)
+	require.NoError(t, err)
+	require.Len(t, locks, 2, "it must return unexpired locks with the prefix")
+	assert.Equal(t, k+"-a", locks[0].Key)
+	assert.Equal(t, "owner-1", locks[0].Owner)
+	assert.Equal(t, k+"-b", locks[1].Key)
+	assert.Equal(t, "owner-2", locks[1].Owner)
+	for _, l := range locks {
+		assert.WithinDuration(t, before.Add(time.Minute), l.Expiry, 5*time.Second)
+	}
+
+	locks, err = a.Locks(ctx, key(t)+"-nothing")
+	require.NoError(t, err)
+	assert.Empty(t, locks)
+}
+
+func testAdminForceUnlock(t *testing.T, a Admin) {
+	ctx := context.Background()
+	k := key(t)
+	m1 := mutex(a, k, "owner-1", 0)
+	m2 := mutex(a, k, "owner-2", 0)
+
+	require.NoError(t, m1.TryLock(ctx))
+	require.NoError(t, a.ForceUnlock(ctx, k))
+	require.NoError(t, m2.TryLock(ctx), "force unlock must release the lock of any owner")
+	assert.True(t, errors.Is(m1.TryLock(ctx), hexa.ErrLockAlreadyAcquired))
+
+	require.NoError(t, a.ForceUnlock(ctx, k))
+	require.NoError(t, a.ForceUnlock(ctx, k), "force unlock must ignore a released lock")
+	require.NoError(t, m1.TryLock(ctx))
+	require.NoError(t, m1.Unlock(ctx))
+}
+
+func testAdminClearExpired(t *testing.T, a Admin) {
+	ctx := context.Background()
+	k := key(t)
+	m := mutex(a, k, "owner-1", TTL)
+	require.NoError(t, m.TryLock(ctx))
+	time.Sleep(TTL + TTL/2)
+
+	n, err := a.ClearExpired(ctx)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, n, int64(0))
+
+	locks, err := a.Locks(ctx, k)
+	require.NoError(t, err)
+	assert.Empty(t, locks)
+	require.NoError(t, mutex(a, k, "owner-2", 0).TryLock(ctx), "an expired lock must not block others")
+}
@@ -0,0 +1,396 @@
+// Package dlmtest is a conformance test suite for hexa.DLM drivers. It checks
+// that a driver follows the Mutex contract documented in hexa.Mutex. Drivers
+// that implement hexa.SemaphoreManager and hexa.RWMutexManager can run the
+// RunSemaphore and RunRWMutex suites too.
+//
+// Example:
+//
+//	func TestConformance(t *testing.T) {
+//		dlmtest.Run(t, func(t *testing.T) hexa.DLM {
+//			dlm, err := NewDlm(DlmOptions{DefaultTTL: time.Minute})
+//			require.NoError(t, err)
+//			return dlm
+//		})
+//	}
+package dlmtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kamva/hexa"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TTL is the short ttl that the suite uses to check expiry of locks.
+const TTL = 300 * time.Millisecond
+
+// Factory returns a new DLM without a default owner. The suite creates
+// mutexes using explicit ttls, and explicit owners except for checking
+// mutexes without owner.
+type Factory func(t *testing.T) hexa.DLM
+
+// Run runs the conformance suite against DLMs returned by newDLM. Each test
+// uses its own unique lock keys, so the suite can run on a shared server.
+func Run(t *testing.T, newDLM Factory) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, d hexa.DLM)
+	}{
+		{"TryLock", testTryLock},
+		{"RefreshOnRelock", testRefreshOnRelock},
+		{"TTLBeginsAtLock", testTTLBeginsAtLock},
+		{"Expiry", testExpiry},
+		{"SameOwner", testSameOwner},
+		{"UnlockByAnotherOwner", testUnlockByAnotherOwner},
+		{"UnlockIsIdempotent", testUnlockIsIdempotent},
+		{"LockWaitsForRelease", testLockWaitsForRelease},
+		{"LockHonoursContext", testLockHonoursContext},
+		{"ConcurrentTryLock", testConcurrentTryLock},
+		{"ConcurrentLock", testConcurrentLock},
+		{"FencingTokens", testFencingTokens},
+		{"ExpiredHolderRefencesAfterAnotherOwner", testExpiredHolderRefence},
+		{"WithoutOwner", testWithoutOwner},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) { tt.fn(t, newDLM(t)) })
+	}
+}
+
+// key returns a unique lock key for the test.
+func key(t *testing.T) string {
+	return fmt.Sprintf("dlmtest-%s-%d", t.Name(), time.Now().UnixNano())
+}
+
+// mutex returns a mutex of the owner. ttl is a minute if it's zero.
+func mutex(d hexa.DLM, key string, owner string, ttl time.Duration) hexa.Mutex {
+	if ttl == 0 {
+		ttl = time.Minute
+	}
+	return d.NewMutexWithOptions(hexa.MutexOptions{Key: key, Owner: owner, TTL: ttl})
+}
+
+func testTryLock(t *testing.T, d hexa.DLM) {
+	ctx := context.Background()
+	k := key(t)
+	m1 := mutex(d, k, "owner-1", 0)
+	m2 := mutex(d, k, "owner-2", 0)
+
+	require.NoError(t, m1.TryLock(ctx))
+	assert.True(t, errors.Is(m2.TryLock(ctx), hexa.ErrLockAlreadyAcquired), "another owner must not acquire a held lock")
+
+	// Other keys are independent.
+	other := mutex(d, key(t)+"-other", "owner-2", 0)
+	require.NoError(t, other.TryLock(ctx))
+
+	require.NoError(t, m1.Unlock(ctx))
+	require.NoError(t, m2.TryLock(ctx))
+	require.NoError(t, m2.Unlock(ctx))
+	require.NoError(t, other.Unlock(ctx))
+}
+
+func testRefreshOnRelock(t *testing.T, d hexa.DLM) {
+	ctx := context.Background()
+	k := key(t)
+	m1 := mutex(d, k, "owner-1", TTL)
+	m2 := mutex(d, k, "owner-2", 0)
+
+	require.NoError(t, m1.TryLock(ctx))
+	time.Sleep(TTL * 2 / 3)
+	require.NoError(t, m1.TryLock(ctx), "relocking a held lock must refresh it")
+	time.Sleep(TTL * 2 / 3)
+	assert.True(t, errors.Is(m2.TryLock(ctx), hexa.ErrLockAlreadyAcquired), "a refreshed lock must not expire")
+
+	time.Sleep(TTL * 2 / 3)
+	require.NoError(t, m1.Lock(ctx), "lock must refresh a held lock too")
+	time.Sleep(TTL * 2 / 3)
+	assert.True(t, errors.Is(m2.TryLock(ctx), hexa.ErrLockAlreadyAcquired), "a refreshed lock must not expire")
+	require.NoError(t, m1.Unlock(ctx))
+}
+
+func testTTLBeginsAtLock(t *testing.T, d hexa.DLM) {
+	ctx := context.Background()
+	k := key(t)
+	m1 := mutex(d, k, "owner-1", TTL)
+	m2 := mutex(d, k, "owner-2", 0)
+
+	time.Sleep(TTL + TTL/2) // the mutex's age must not count.
+	require.NoError(t, m1.TryLock(ctx))
+	assert.True(t, errors.Is(m2.TryLock(ctx), hexa.ErrLockAlreadyAcquired), "ttl must begin at lock time")
+	require.NoError(t, m1.Unlock(ctx))
+}
+
+func testExpiry(t *testing.T, d hexa.DLM) {
+	ctx := context.Background()
+	k := key(t)
+	m1 := mutex(d, k, "owner-1", TTL)
+	m2 := mutex(d, k, "owner-2", 0)
+
+	require.NoError(t, m1.TryLock(ctx))
+	time.Sleep(TTL + TTL/2)
+	require.NoError(t, m2.TryLock(ctx), "an expired lock must be acquirable")
+	assert.True(t, errors.Is(m1.TryLock(ctx), hexa.ErrLockAlreadyAcquired), "the previous owner must not reacquire the lock")
+
+	// Unlocking the expired mutex must not release the new owner's lock.
+	require.NoError(t, m1.Unlock(ctx))
+	assert.True(t, errors.Is(m1.TryLock(ctx), hexa.ErrLockAlreadyAcquired))
+	require.NoError(t, m2.Unlock(ctx))
+}
+
+func testSameOwner(t *testing.T, d hexa.DLM) {
+	ctx := context.Background()
+	k := key(t)
+	m1 := mutex(d, k, "owner-1", 0)
+	m2 := mutex(d, k, "owner-1", 0)
+	other := mutex(d, k, "owner-2", 0)
+
+	require.NoError(t, m1.TryLock(ctx))
+	require.NoError(t, m2.TryLock(ctx), "mutexes of the same owner must share the lock")
+
+	require.NoError(t, m2.Unlock(ctx))
+	require.NoError(t, other.TryLock(ctx), "mutexes of the same owner must unlock each other")
+	require.NoError(t, other.Unlock(ctx))
+}
+
+func testUnlockByAnotherOwner(t *testing.T, d hexa.DLM) {
+	ctx := context.Background()
+	k := key(t)
+	m1 := mutex(d, k, "owner-1", 0)
+	m2 := mutex(d, k, "owner-2", 0)
+
+	require.NoError(t, m1.TryLock(ctx))
+	require.NoError(t, m2.Unlock(ctx))
+	assert.True(t, errors.Is(m2.TryLock(ctx), hexa.ErrLockAlreadyAcquired), "another owner must not release the lock")
+	require.NoError(t, m1.Unlock(ctx))
+}
+
+func testUnlockIsIdempotent(t *testing.T, d hexa.DLM) {
+	ctx := context.Background()
+	m := mutex(d, key(t), "owner-1", 0)
+
+	require.NoError(t, m.Unlock(ctx), "unlocking a mutex that is not locked must be a no-op")
+	require.NoError(t, m.TryLock(ctx))
+	require.NoError(t, m.Unlock(ctx))
+	require.NoError(t, m.Unlock(ctx), "unlocking a released lock must be a no-op")
+
+	// The mutex is usable after unlock.
+	require.NoError(t, m.TryLock(ctx))
+	require.NoError(t, m.Unlock(ctx))
+}
+
+func testLockWaitsForRelease(t *testing.T, d hexa.DLM) {
+	ctx := context.Background()
+	k := key(t)
+	m1 := mutex(d, k, "owner-1", 0)
+	m2 := mutex(d, k, "owner-2", 0)
+	require.NoError(t, m1.Lock(ctx))
+
+	locked := make(chan error, 1)
+	go func() { locked <- m2.Lock(ctx) }()
+
+	select {
+	case err := <-locked:
+		t.Fatalf("lock must wait for the held lock, got: %v", err)
+	case <-time.After(TTL / 2):
+	}
+
+	require.NoError(t, m1.Unlock(ctx))
+	select {
+	case err := <-locked:
+		require.NoError(t, err)
+	case <-time.After(10 * time.Second):
+		t.Fatal("lock did not acquire the released lock")
+	}
+	require.NoError(t, m2.Unlock(ctx))
+}
+
+func testLockHonoursContext(t *testing.T, d hexa.DLM) {
+	k := key(t)
+	m1 := mutex(d, k, "owner-1", 0)
+	m2 := mutex(d, k, "owner-2", 0)
+	require.NoError(t, m1.Lock(context.Background()))
+	defer func() { require.NoError(t, m1.Unlock(context.Background())) }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), TTL/2)
+	defer cancel()
+	start := time.Now()
+	err := m2.Lock(ctx)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded), "lock must return the context's error, got: %v", err)
+	assert.Less(t, int64(time.Since(start)), int64(10*time.Second), "lock must return when the context is done")
+
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	assert.Error(t, m2.Lock(ctx), "lock must not wait on a canceled context")
+}
+
+func testConcurrentTryLock(t *testing.T, d hexa.DLM) {
+	ctx := context.Background()
+	k := key(t)
+	const n = 10
+
+	mutexes := make([]hexa.Mutex, n)
+	for i := range mutexes {
+		mutexes[i] = mutex(d, k, fmt.Sprintf("owner-%d", i), 0)
+	}
+
+	var acquired int32
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	errs := make(chan error, n)
+	for _, m := range mutexes {
+		wg.Add(1)
+		go func(m hexa.Mutex) {
+			defer wg.Done()
+			<-start
+			err := m.TryLock(ctx)
+			if err == nil {
+				atomic.AddInt32(&acquired, 1)
+				return
+			}
+			if !errors.Is(err, hexa.ErrLockAlreadyAcquired) {
+				errs <- err
+			}
+		}(m)
+	}
+	close(start)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		require.NoError(t, err)
+	}
+	assert.Equal(t, int32(1), acquired, "just one owner must acquire the lock")
+
+	for _, m := range mutexes {
+		require.NoError(t, m.Unlock(ctx))
+	}
+}
+
+func testConcurrentLock(t *testing.T, d hexa.DLM) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	k := key(t)
+	const n = 5
+
+	var holders, maxHolders, done int32
+	var wg sync.WaitGroup
+	errs := make(chan error, n)
+	for i := 0; i < n; i++ {
+		m := mutex(d, k, fmt.Sprintf("owner-%d", i), 0)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := m.Lock(ctx); err != nil {
+				errs <- err
+				return
+			}
+
+			h := atomic.AddInt32(&holders, 1)
+			for {
+				cur := atomic.LoadInt32(&maxHolders)
+				if h <= cur || atomic.CompareAndSwapInt32(&maxHolders, cur, h) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			atomic.AddInt32(&holders, -1)
+			atomic.AddInt32(&done, 1)
+
+			if err := m.Unlock(ctx); err != nil {
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		require.NoError(t, err)
+	}
+	assert.Equal(t, int32(n), done, "all mutexes must acquire the lock")
+	assert.Equal(t, int32(1), maxHolders, "the lock must have one holder at a time")
+}
+
+// testFencingTokens runs just if mutexes of the DLM are hexa.FencedMutex.
+func testFencingTokens(t *testing.T, d hexa.DLM) {
+	ctx := context.Background()
+	k := key(t)
+	m1, ok := mutex(d, k, "owner-1", TTL).(hexa.FencedMutex)
+	if !ok {
+		t.Skip("mutexes of the DLM are not fenced")
+	}
+	m2 := mutex(d, k, "owner-2", 0).(hexa.FencedMutex)
+	shared := mutex(d, k, "owner-1", TTL).(hexa.FencedMutex)
+
+	assert.Zero(t, m1.Token(), "token of a mutex which is not locked must be zero")
+	require.NoError(t, m1.TryLock(ctx))
+	t1 := m1.Token()
+	assert.Positive(t, t1)
+	require.NoError(t, m1.TryLock(ctx))
+	assert.Equal(t, t1, m1.Token(), "refresh must keep the token")
+	require.NoError(t, shared.TryLock(ctx))
+	assert.Equal(t, t1, shared.Token(), "mutexes of the same owner must share the token")
+
+	// A new owner gets a greater token after expiry.
+	time.Sleep(TTL + TTL/2)
+	require.NoError(t, m2.TryLock(ctx))
+	t2 := m2.Token()
+	assert.Greater(t, t2, t1)
+	assert.True(t, errors.Is(m1.TryLock(ctx), hexa.ErrLockAlreadyAcquired))
+	assert.Zero(t, m1.Token(), "token of a lost lock must be zero")
+
+	require.NoError(t, m2.Unlock(ctx))
+	assert.Zero(t, m2.Token(), "token of an unlocked mutex must be zero")
+	require.NoError(t, m1.Lock(ctx))
+	assert.Greater(t, m1.Token(), t2)
+	require.NoError(t, m1.Unlock(ctx))
+}
+
+// testExpiredHolderRefence checks an owner whose lock is expired can't get
+// a token while another owner holds the lock, so its token never exceeds
+// the token of the current holder.
+func testExpiredHolderRefence(t *testing.T, d hexa.DLM) {
+	ctx := context.Background()
+	k := key(t)
+	m1, ok := mutex(d, k, "owner-1", TTL).(hexa.FencedMutex)
+	if !ok {
+		t.Skip("mutexes of the DLM are not fenced")
+	}
+	m2 := mutex(d, k, "owner-2", 0).(hexa.FencedMutex)
+
+	require.NoError(t, m1.TryLock(ctx))
+	time.Sleep(TTL + TTL/2)
+	require.NoError(t, m2.TryLock(ctx))
+	t2 := m2.Token()
+
+	assert.True(t, errors.Is(m1.TryLock(ctx), hexa.ErrLockAlreadyAcquired))
+	assert.Zero(t, m1.Token())
+	waitCtx, cancel := context.WithTimeout(ctx, TTL)
+	defer cancel()
+	assert.Error(t, m1.Lock(waitCtx))
+	assert.Zero(t, m1.Token())
+
+	require.NoError(t, m2.TryLock(ctx))
+	assert.Equal(t, t2, m2.Token(), "the expired holder must not change the holder's token")
+	require.NoError(t, m2.Unlock(ctx))
+}
+
+// testWithoutOwner checks mutexes without owner don't share their lock.
+func testWithoutOwner(t *testing.T, d hexa.DLM) {
+	ctx := context.Background()
+	k := key(t)
+	m1 := mutex(d, k, "", 0)
+	m2 := mutex(d, k, "", 0)
+
+	require.NoError(t, m1.TryLock(ctx))
+	assert.True(t, errors.Is(m2.TryLock(ctx), hexa.ErrLockAlreadyAcquired), "mutexes without owner must not share the lock")
+	require.NoError(t, m1.Unlock(ctx))
+}
@@ -0,0 +1,181 @@
+package dlmtest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kamva/hexa"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// RWMutexFactory returns a new read/write lock manager without a default
+// owner, usually a DLM.
+type RWMutexFactory func(t *testing.T) hexa.RWMutexManager
+
+// RunRWMutex runs the conformance suite against read/write locks of the
+// managers returned by newManager.
+func RunRWMutex(t *testing.T, newManager RWMutexFactory) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, rm hexa.RWMutexManager)
+	}{
+		{"SharedReaders", testRWMutexSharedReaders},
+		{"ExclusiveWriter", testRWMutexExclusiveWriter},
+		{"Upgrade", testRWMutexUpgrade},
+		{"Expiry", testRWMutexExpiry},
+		{"UnlockIsIdempotent", testRWMutexUnlockIsIdempotent},
+		{"LockWaitsForReaders", testRWMutexLockWaitsForReaders},
+		{"RLockHonoursContext", testRWMutexRLockHonoursContext},
+		{"WithoutOwner", testRWMutexWithoutOwner},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) { tt.fn(t, newManager(t)) })
+	}
+}
+
+// rwMutex returns a read/write lock of the owner. ttl is a minute if it's zero.
+func rwMutex(rm hexa.RWMutexManager, key string, owner string, ttl time.Duration) hexa.RWMutex {
+	if ttl == 0 {
+		ttl = time.Minute
+	}
+	return rm.NewRWMutex(hexa.MutexOptions{Key: key, Owner: owner, TTL: ttl})
+}
+
+func testRWMutexSharedReaders(t *testing.T, rm hexa.RWMutexManager) {
+	ctx := context.Background()
+	k := key(t)
+	r1 := rwMutex(rm, k, "owner-1", 0)
+	r2 := rwMutex(rm, k, "owner-2", 0)
+	w := rwMutex(rm, k, "owner-3", 0)
+
+	require.NoError(t, r1.TryRLock(ctx))
+	require.NoError(t, r2.TryRLock(ctx), "readers must share the lock")
+	require.NoError(t, r1.TryRLock(ctx), "read locking again must refresh the lock")
+	assert.True(t, errors.Is(w.TryLock(ctx), hexa.ErrLockAlreadyAcquired), "a writer must wait for readers")
+
+	require.NoError(t, r1.RUnlock(ctx))
+	assert.True(t, errors.Is(w.TryLock(ctx), hexa.ErrLockAlreadyAcquired), "a writer must wait for all readers")
+	require.NoError(t, r2.RUnlock(ctx))
+	require.NoError(t, w.TryLock(ctx))
+	require.NoError(t, w.Unlock(ctx))
+}
+
+func testRWMutexExclusiveWriter(t *testing.T, rm hexa.RWMutexManager) {
+	ctx := context.Background()
+	k := key(t)
+	w1 := rwMutex(rm, k, "owner-1", 0)
+	w2 := rwMutex(rm, k, "owner-2", 0)
+	shared := rwMutex(rm, k, "owner-1", 0)
+
+	require.NoError(t, w1.TryLock(ctx))
+	require.NoError(t, w1.TryLock(ctx), "write locking again must refresh the lock")
+	require.NoError(t, shared.TryLock(ctx), "locks of the same owner must share the write lock")
+	assert.True(t, errors.Is(w2.TryLock(ctx), hexa.ErrLockAlreadyAcquired), "the write lock must be exclusive")
+	assert.True(t, errors.Is(w2.TryRLock(ctx), hexa.ErrLockAlreadyAcquired), "readers must wait for the writer")
+
+	require.NoError(t, w2.Unlock(ctx))
+	assert.True(t, errors.Is(w2.TryRLock(ctx), hexa.ErrLockAlreadyAcquired), "another owner must not unlock the writer")
+
+	require.NoError(t, shared.Unlock(ctx))
+	require.NoError(t, w2.TryRLock(ctx))
+	require.NoError(t, w2.RUnlock(ctx))
+}
+
+func testRWMutexUpgrade(t *testing.T, rm hexa.RWMutexManager) {
+	ctx := context.Background()
+	m := rwMutex(rm, key(t), "owner-1", 0)
+
+	require.NoError(t, m.TryRLock(ctx))
+	require.NoError(t, m.TryLock(ctx), "the only reader must be able to write lock")
+	require.NoError(t, m.Unlock(ctx))
+	require.NoError(t, m.RUnlock(ctx))
+}
+
+func testRWMutexExpiry(t *testing.T, rm hexa.RWMutexManager) {
+	ctx := context.Background()
+	k := key(t)
+	r := rwMutex(rm, k, "owner-1", TTL)
+	w1 := rwMutex(rm, k, "owner-2", TTL)
+	w2 := rwMutex(rm, k, "owner-3", 0)
+
+	require.NoError(t, r.TryRLock(ctx))
+	time.Sleep(TTL + TTL/2)
+	require.NoError(t, w1.TryLock(ctx), "an expired reader must not block writers")
+
+	time.Sleep(TTL + TTL/2)
+	require.NoError(t, w2.TryLock(ctx), "an expired writer must not block writers")
+	require.NoError(t, w1.Unlock(ctx))
+	assert.True(t, errors.Is(r.TryRLock(ctx), hexa.ErrLockAlreadyAcquired), "unlocking an expired writer must not release others")
+	require.NoError(t, w2.Unlock(ctx))
+}
+
+func testRWMutexUnlockIsIdempotent(t *testing.T, rm hexa.RWMutexManager) {
+	ctx := context.Background()
+	m := rwMutex(rm, key(t), "owner-1", 0)
+
+	require.NoError(t, m.Unlock(ctx))
+	require.NoError(t, m.RUnlock(ctx))
+	require.NoError(t, m.TryLock(ctx))
+	require.NoError(t, m.Unlock(ctx))
+	require.NoError(t, m.Unlock(ctx))
+	require.NoError(t, m.TryRLock(ctx))
+	require.NoError(t, m.RUnlock(ctx))
+	require.NoError(t, m.RUnlock(ctx))
+}
+
+func testRWMutexLockWaitsForReaders(t *testing.T, rm hexa.RWMutexManager) {
+	ctx := context.Background()
+	k := key(t)
+	r := rwMutex(rm, k, "owner-1", 0)
+	w := rwMutex(rm, k, "owner-2", 0)
+	require.NoError(t, r.RLock(ctx))
+
+	locked := make(chan error, 1)
+	go func() { locked <- w.Lock(ctx) }()
+
+	select {
+	case err := <-locked:
+		t.Fatalf("lock must wait for readers, got: %v", err)
+	case <-time.After(TTL / 2):
+	}
+
+	require.NoError(t, r.RUnlock(ctx))
+	select {
+	case err := <-locked:
+		require.NoError(t, err)
+	case <-time.After(10 * time.Second):
+		t.Fatal("lock did not acquire the released lock")
+	}
+	require.NoError(t, w.Unlock(ctx))
+}
+
+func testRWMutexRLockHonoursContext(t *testing.T, rm hexa.RWMutexManager) {
+	k := key(t)
+	w := rwMutex(rm, k, "owner-1", 0)
+	r := rwMutex(rm, k, "owner-2", 0)
+	require.NoError(t, w.Lock(context.Background()))
+	defer func() { require.NoError(t, w.Unlock(context.Background())) }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), TTL/2)
+	defer cancel()
+	err := r.RLock(ctx)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded), "rlock must return the context's error, got: %v", err)
+}
+
+// testRWMutexWithoutOwner checks read/write locks without owner don't
+// share the write lock.
+func testRWMutexWithoutOwner(t *testing.T, rm hexa.RWMutexManager) {
+	ctx := context.Background()
+	k := key(t)
+	w1 := rwMutex(rm, k, "", 0)
+	w2 := rwMutex(rm, k, "", 0)
+
+	require.NoError(t, w1.TryLock(ctx))
+	assert.True(t, errors.Is(w2.TryLock(ctx), hexa.ErrLockAlreadyAcquired), "locks without owner must not share the write lock")
+	require.NoError(t, w1.Unlock(ctx))
+}
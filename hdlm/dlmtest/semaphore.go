@@ -0,0 +1,207 @@
+package dlmtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kamva/hexa"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// SemaphoreFactory returns a new semaphore manager without a default
+// owner, usually a DLM.
+type SemaphoreFactory func(t *testing.T) hexa.SemaphoreManager
+
+// RunSemaphore runs the conformance suite against semaphores of the
+// managers returned by newManager.
+func RunSemaphore(t *testing.T, newManager SemaphoreFactory) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, sm hexa.SemaphoreManager)
+	}{
+		{"Permits", testSemaphorePermits},
+		{"SameOwner", testSemaphoreSameOwner},
+		{"Expiry", testSemaphoreExpiry},
+		{"ReleaseIsIdempotent", testSemaphoreReleaseIsIdempotent},
+		{"AcquireWaitsForRelease", testSemaphoreAcquireWaitsForRelease},
+		{"AcquireHonoursContext", testSemaphoreAcquireHonoursContext},
+		{"Concurrency", testSemaphoreConcurrency},
+		{"WithoutOwner", testSemaphoreWithoutOwner},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) { tt.fn(t, newManager(t)) })
+	}
+}
+
+// semaphore returns a semaphore of the owner. ttl is a minute if it's zero.
+func semaphore(sm hexa.SemaphoreManager, key string, owner string, permits int, ttl time.Duration) hexa.Semaphore {
+	if ttl == 0 {
+		ttl = time.Minute
+	}
+	return sm.NewSemaphore(hexa.SemaphoreOptions{Key: key, Owner: owner, TTL: ttl, Permits: permits})
+}
+
+func testSemaphorePermits(t *testing.T, sm hexa.SemaphoreManager) {
+	ctx := context.Background()
+	k := key(t)
+	s1 := semaphore(sm, k, "owner-1", 2, 0)
+	s2 := semaphore(sm, k, "owner-2", 2, 0)
+	s3 := semaphore(sm, k, "owner-3", 2, 0)
+
+	require.NoError(t, s1.TryAcquire(ctx))
+	require.NoError(t, s2.TryAcquire(ctx))
+	assert.True(t, errors.Is(s3.TryAcquire(ctx), hexa.ErrLockAlreadyAcquired), "a full semaphore must not be acquirable")
+	require.NoError(t, s1.TryAcquire(ctx), "acquiring a held permit must refresh it")
+
+	require.NoError(t, s1.Release(ctx))
+	require.NoError(t, s3.TryAcquire(ctx))
+	require.NoError(t, s2.Release(ctx))
+	require.NoError(t, s3.Release(ctx))
+}
+
+func testSemaphoreSameOwner(t *testing.T, sm hexa.SemaphoreManager) {
+	ctx := context.Background()
+	k := key(t)
+	s1 := semaphore(sm, k, "owner-1", 1, 0)
+	s2 := semaphore(sm, k, "owner-1", 1, 0)
+	other := semaphore(sm, k, "owner-2", 1, 0)
+
+	require.NoError(t, s1.TryAcquire(ctx))
+	require.NoError(t, s2.TryAcquire(ctx), "semaphores of the same owner must share the permit")
+
+	require.NoError(t, s2.Release(ctx))
+	require.NoError(t, other.TryAcquire(ctx), "semaphores of the same owner must release each other")
+	require.NoError(t, other.Release(ctx))
+}
+
+func testSemaphoreExpiry(t *testing.T, sm hexa.SemaphoreManager) {
+	ctx := context.Background()
+	k := key(t)
+	s1 := semaphore(sm, k, "owner-1", 1, TTL)
+	s2 := semaphore(sm, k, "owner-2", 1, 0)
+
+	time.Sleep(TTL + TTL/2) // the semaphore's age must not count.
+	require.NoError(t, s1.TryAcquire(ctx))
+	assert.True(t, errors.Is(s2.TryAcquire(ctx), hexa.ErrLockAlreadyAcquired), "ttl must begin at acquire time")
+
+	time.Sleep(TTL + TTL/2)
+	require.NoError(t, s2.TryAcquire(ctx), "an expired permit must be acquirable")
+	require.NoError(t, s1.Release(ctx))
+	assert.True(t, errors.Is(s1.TryAcquire(ctx), hexa.ErrLockAlreadyAcquired), "releasing an expired permit must not release others")
+	require.NoError(t, s2.Release(ctx))
+}
+
+func testSemaphoreReleaseIsIdempotent(t *testing.T, sm hexa.SemaphoreManager) {
+	ctx := context.Background()
+	s := semaphore(sm, key(t), "owner-1", 1, 0)
+
+	require.NoError(t, s.Release(ctx), "releasing a semaphore that is not acquired must be a no-op")
+	require.NoError(t, s.TryAcquire(ctx))
+	require.NoError(t, s.Release(ctx))
+	require.NoError(t, s.Release(ctx), "releasing a released permit must be a no-op")
+}
+
+func testSemaphoreAcquireWaitsForRelease(t *testing.T, sm hexa.SemaphoreManager) {
+	ctx := context.Background()
+	k := key(t)
+	s1 := semaphore(sm, k, "owner-1", 1, 0)
+	s2 := semaphore(sm, k, "owner-2", 1, 0)
+	require.NoError(t, s1.Acquire(ctx))
+
+	acquired := make(chan error, 1)
+	go func() { acquired <- s2.Acquire(ctx) }()
+
+	select {
+	case err := <-acquired:
+		t.Fatalf("acquire must wait for a free permit, got: %v", err)
+	case <-time.After(TTL / 2):
+	}
+
+	require.NoError(t, s1.Release(ctx))
+	select {
+	case err := <-acquired:
+		require.NoError(t, err)
+	case <-time.After(10 * time.Second):
+		t.Fatal("acquire did not acquire the released permit")
+	}
+	require.NoError(t, s2.Release(ctx))
+}
+
+func testSemaphoreAcquireHonoursContext(t *testing.T, sm hexa.SemaphoreManager) {
+	k := key(t)
+	s1 := semaphore(sm, k, "owner-1", 1, 0)
+	s2 := semaphore(sm, k, "owner-2", 1, 0)
+	require.NoError(t, s1.Acquire(context.Background()))
+	defer func() { require.NoError(t, s1.Release(context.Background())) }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), TTL/2)
+	defer cancel()
+	err := s2.Acquire(ctx)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded), "acquire must return the context's error, got: %v", err)
+}
+
+func testSemaphoreConcurrency(t *testing.T, sm hexa.SemaphoreManager) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	k := key(t)
+	const n, permits = 8, 3
+
+	var holders, maxHolders, done int32
+	var wg sync.WaitGroup
+	errs := make(chan error, n)
+	for i := 0; i < n; i++ {
+		s := semaphore(sm, k, fmt.Sprintf("owner-%d", i), permits, 0)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := s.Acquire(ctx); err != nil {
+				errs <- err
+				return
+			}
+
+			h := atomic.AddInt32(&holders, 1)
+			for {
+				cur := atomic.LoadInt32(&maxHolders)
+				if h <= cur || atomic.CompareAndSwapInt32(&maxHolders, cur, h) {
+					break
+				}
+			}
+			time.Sleep(20 * time.Millisecond)
+			atomic.AddInt32(&holders, -1)
+			atomic.AddInt32(&done, 1)
+
+			if err := s.Release(ctx); err != nil {
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		require.NoError(t, err)
+	}
+	assert.Equal(t, int32(n), done, "all owners must acquire a permit")
+	assert.LessOrEqual(t, maxHolders, int32(permits), "holders must not exceed the permits")
+}
+
+// testSemaphoreWithoutOwner checks semaphores without owner don't share
+// their permits.
+func testSemaphoreWithoutOwner(t *testing.T, sm hexa.SemaphoreManager) {
+	ctx := context.Background()
+	k := key(t)
+	s1 := semaphore(sm, k, "", 1, 0)
+	s2 := semaphore(sm, k, "", 1, 0)
+
+	require.NoError(t, s1.TryAcquire(ctx))
+	assert.True(t, errors.Is(s2.TryAcquire(ctx), hexa.ErrLockAlreadyAcquired), "semaphores without owner must not share a permit")
+	require.NoError(t, s1.Release(ctx))
+}
@@ -0,0 +1,81 @@
+// Package lockwait implements waiting for held locks with exponential
+// backoff and jitter, shared by the DLM drivers.
+package lockwait
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+
+	"github.com/kamva/hexa"
+)
+
+// DefaultInterval is the waiting interval when the interval is zero.
+const DefaultInterval = 100 * time.Millisecond
+
+type Options struct {
+	// Interval is the first waiting time before trying again.
+	Interval time.Duration
+	// MaxInterval enables exponential backoff: the interval doubles on
+	// each try up to MaxInterval. zero means a fixed interval.
+	MaxInterval time.Duration
+	// Jitter randomizes each interval by up to this fraction of it,
+	// e.g., 0.2 means ±20%.
+	Jitter float64
+	// MaxWait limits the total waiting time. zero means no limit.
+	MaxWait time.Duration
+}
+
+// Delay returns the waiting time before the try number n (zero-based).
+func (o Options) Delay(n int) time.Duration {
+	d := o.Interval
+	if d <= 0 {
+		d = DefaultInterval
+	}
+
+	for i := 0; i < n && d < o.MaxInterval; i++ {
+		d *= 2
+		if d > o.MaxInterval {
+			d = o.MaxInterval
+		}
+	}
+
+	if o.Jitter > 0 {
+		d += time.Duration(float64(d) * o.Jitter * (2*rand.Float64() - 1))
+	}
+	return d
+}
+
+// Wait calls tryLock until it acquires the lock or returns an error other
+// than hexa.ErrLockAlreadyAcquired. It tries again immediately when it
+// receives from released, which is optional. It returns the
+// hexa.ErrLockAlreadyAcquired error when it waits longer than MaxWait.
+func Wait(c context.Context, o Options, tryLock func(c context.Context) error, released <-chan struct{}) error {
+	var deadline <-chan time.Time
+	if o.MaxWait > 0 {
+		t := time.NewTimer(o.MaxWait)
+		defer t.Stop()
+		deadline = t.C
+	}
+
+	for n := 0; ; n++ {
+		err := tryLock(c)
+		if !errors.Is(err, hexa.ErrLockAlreadyAcquired) {
+			return err
+		}
+
+		t := time.NewTimer(o.Delay(n))
+		select {
+		case <-c.Done():
+			t.Stop()
+			return c.Err()
+		case <-deadline:
+			t.Stop()
+			return err
+		case <-released:
+			t.Stop()
+		case <-t.C:
+		}
+	}
+}
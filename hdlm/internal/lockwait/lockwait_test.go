@@ -0,0 +1,83 @@
+package lockwait
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kamva/hexa"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptions_Delay(t *testing.T) {
+	fixed := Options{Interval: 10 * time.Millisecond}
+	assert.Equal(t, 10*time.Millisecond, fixed.Delay(0))
+	assert.Equal(t, 10*time.Millisecond, fixed.Delay(5))
+
+	assert.Equal(t, DefaultInterval, Options{}.Delay(3))
+
+	backoff := Options{Interval: 10 * time.Millisecond, MaxInterval: 50 * time.Millisecond}
+	assert.Equal(t, 10*time.Millisecond, backoff.Delay(0))
+	assert.Equal(t, 20*time.Millisecond, backoff.Delay(1))
+	assert.Equal(t, 40*time.Millisecond, backoff.Delay(2))
+	assert.Equal(t, 50*time.Millisecond, backoff.Delay(3))
+	assert.Equal(t, 50*time.Millisecond, backoff.Delay(100))
+
+	jitter := Options{Interval: 100 * time.Millisecond, Jitter: 0.2}
+	for i := 0; i < 100; i++ {
+		d := jitter.Delay(0)
+		assert.GreaterOrEqual(t, d, 80*time.Millisecond)
+		assert.LessOrEqual(t, d, 120*time.Millisecond)
+	}
+}
+
+// tryLock fails with hexa.ErrLockAlreadyAcquired until it's called n times.
+func tryLock(n int, calls *int) func(context.Context) error {
+	return func(context.Context) error {
+		*calls++
+		if *calls < n {
+			return hexa.ErrLockAlreadyAcquired
+		}
+		return nil
+	}
+}
+
+func TestWait(t *testing.T) {
+	var calls int
+	err := Wait(context.Background(), Options{Interval: time.Millisecond}, tryLock(3, &calls), nil)
+	require.NoError(t, err)
+	assert.Equal(t, 3, calls)
+
+	want := errors.New("connection refused")
+	err = Wait(context.Background(), Options{}, func(context.Context) error { return want }, nil)
+	assert.ErrorIs(t, err, want)
+}
+
+func TestWait_MaxWait(t *testing.T) {
+	var calls int
+	start := time.Now()
+	err := Wait(context.Background(), Options{Interval: 10 * time.Millisecond, MaxWait: 50 * time.Millisecond}, tryLock(1000, &calls), nil)
+	assert.ErrorIs(t, err, hexa.ErrLockAlreadyAcquired)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestWait_Context(t *testing.T) {
+	var calls int
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err := Wait(ctx, Options{Interval: 10 * time.Millisecond}, tryLock(1000, &calls), nil)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestWait_Released(t *testing.T) {
+	released := make(chan struct{}, 1)
+	released <- struct{}{}
+
+	var calls int
+	start := time.Now()
+	err := Wait(context.Background(), Options{Interval: time.Minute}, tryLock(2, &calls), released)
+	require.NoError(t, err)
+	assert.Less(t, time.Since(start), time.Second, "it must try again on release")
+}
@@ -0,0 +1,5 @@
+// Package leader implements leader election on top of any hexa.DLM, so
+// just one instance of a service is active in the cluster. The elector
+// is a Runnable and Shutdownable service, register it in the service
+// registry to run it and step down on shutdown.
+package leader
@@ -0,0 +1,199 @@
+package leader
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/kamva/hexa"
+	"github.com/kamva/hexa/hlog"
+	"github.com/kamva/tracer"
+)
+
+// DefaultTTL is the default ttl of the leader's lease.
+const DefaultTTL = 15 * time.Second
+
+type Options struct {
+	DLM hexa.DLM
+	// Key is the lock key of the election.
+	Key string
+	// Owner identifies this instance, so it must be unique between
+	// instances. If it's empty, we generate a unique owner from the
+	// hostname. We don't use the DLM's default owner, because instances
+	// often share it.
+	Owner string
+	// TTL is the ttl of the leader's lease. The leader renews its
+	// lease every TTL/3, followers try to take over the lease every
+	// RetryInterval. Default value is DefaultTTL.
+	TTL time.Duration
+	// RetryInterval default value is TTL/3.
+	RetryInterval time.Duration
+	// OnElected is called in its own goroutine when this instance becomes
+	// the leader. ctx is canceled as soon as it's not the leader anymore,
+	// so stop your work then.
+	OnElected func(ctx context.Context)
+	// OnStepDown is called when this instance is not the leader anymore,
+	// because it lost its lease or it's shutting down.
+	OnStepDown func()
+}
+
+// Elector elects a leader among instances that use the same key.
+type Elector struct {
+	o     Options
+	mutex hexa.Mutex
+
+	mu     sync.RWMutex
+	leader bool
+	cancel context.CancelFunc // cancels the leadership context.
+
+	runOnce  sync.Once
+	stopOnce sync.Once
+	stop     chan struct{}
+	done     chan error // is closed when the election stops.
+}
+
+func New(o Options) (*Elector, error) {
+	if o.DLM == nil || o.Key == "" {
+		return nil, tracer.Trace(errors.New("leader elector needs a DLM and a key"))
+	}
+	if o.TTL == 0 {
+		o.TTL = DefaultTTL
+	}
+	if o.RetryInterval == 0 {
+		o.RetryInterval = o.TTL / 3
+	}
+	if o.Owner == "" {
+		o.Owner = uniqueOwner()
+	}
+
+	return &Elector{
+		o:     o,
+		mutex: o.DLM.NewMutexWithOptions(hexa.MutexOptions{Key: o.Key, Owner: o.Owner, TTL: o.TTL}),
+		stop:  make(chan struct{}),
+		done:  make(chan error),
+	}, nil
+}
+
+// uniqueOwner returns the hostname with a random suffix.
+func uniqueOwner() string {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = "elector"
+	}
+
+	b := make([]byte, 8)
+	_, _ = rand.Read(b) // it never returns an error.
+	return host + "-" + hex.EncodeToString(b)
+}
+
+// IsLeader returns true if this instance is the leader.
+func (e *Elector) IsLeader() bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.leader
+}
+
+// Run runs the election in the background. The returned channel is
+// closed when the elector shuts down.
+func (e *Elector) Run() (<-chan error, error) {
+	e.runOnce.Do(func() { go e.run() })
+	return e.done, nil
+}
+
+func (e *Elector) run() {
+	defer close(e.done)
+	for {
+		e.tryLead()
+
+		interval := e.o.RetryInterval
+		if e.IsLeader() {
+			interval = e.o.TTL / 3
+		}
+
+		select {
+		case <-e.stop:
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+// tryLead takes or renews the lease and steps down if it fails to renew it.
+func (e *Elector) tryLead() {
+	ctx, cancel := context.WithTimeout(context.Background(), e.o.TTL/3)
+	defer cancel()
+
+	err := e.mutex.TryLock(ctx)
+	if err == nil {
+		e.elected()
+		return
+	}
+
+	if !errors.Is(err, hexa.ErrLockAlreadyAcquired) {
+		hlog.Error("leader election failed to acquire the lease", hlog.String("key", e.o.Key), hlog.Err(err))
+	}
+	if e.IsLeader() {
+		hlog.Warn("lost the leadership", hlog.String("key", e.o.Key), hlog.Err(err))
+		e.stepDown()
+	}
+}
+
+func (e *Elector) elected() {
+	e.mu.Lock()
+	if e.leader {
+		e.mu.Unlock()
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	e.leader = true
+	e.cancel = cancel
+	e.mu.Unlock()
+
+	hlog.Info("elected as the leader", hlog.String("key", e.o.Key))
+	if e.o.OnElected != nil {
+		go e.o.OnElected(ctx)
+	}
+}
+
+func (e *Elector) stepDown() {
+	e.mu.Lock()
+	if !e.leader {
+		e.mu.Unlock()
+		return
+	}
+	e.leader = false
+	e.cancel()
+	e.mu.Unlock()
+
+	if e.o.OnStepDown != nil {
+		e.o.OnStepDown()
+	}
+}
+
+// Shutdown stops the election. If this instance is the leader, it
+// steps down and releases the lease, so another instance can take
+// it over immediately.
+func (e *Elector) Shutdown(ctx context.Context) error {
+	e.stopOnce.Do(func() { close(e.stop) })
+	e.runOnce.Do(func() { close(e.done) }) // it's not running.
+
+	select {
+	case <-ctx.Done():
+		return tracer.Trace(ctx.Err())
+	case <-e.done:
+	}
+
+	if !e.IsLeader() {
+		return nil
+	}
+	e.stepDown()
+	hlog.Info("stepped down from the leadership", hlog.String("key", e.o.Key))
+	return tracer.Trace(e.mutex.Unlock(ctx))
+}
+
+var _ hexa.Runnable = &Elector{}
+var _ hexa.Shutdownable = &Elector{}
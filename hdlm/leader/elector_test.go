@@ -0,0 +1,158 @@
+package leader
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kamva/hexa"
+	"github.com/kamva/hexa/hdlm/memlock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type callbacks struct {
+	elected     int32
+	steppedDown int32
+	canceled    int32
+}
+
+func newElector(t *testing.T, dlm hexa.DLM, owner string, cb *callbacks) *Elector {
+	t.Helper()
+	e, err := New(Options{
+		DLM:   dlm,
+		Key:   "election",
+		Owner: owner,
+		TTL:   150 * time.Millisecond,
+		OnElected: func(ctx context.Context) {
+			atomic.AddInt32(&cb.elected, 1)
+			<-ctx.Done()
+			atomic.AddInt32(&cb.canceled, 1)
+		},
+		OnStepDown: func() { atomic.AddInt32(&cb.steppedDown, 1) },
+	})
+	require.NoError(t, err)
+	return e
+}
+
+func TestElector(t *testing.T) {
+	dlm, err := memlock.NewDlm(memlock.DlmOptions{})
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	var cb1, cb2 callbacks
+	e1 := newElector(t, dlm, "a", &cb1)
+	e2 := newElector(t, dlm, "b", &cb2)
+
+	_, err = e1.Run()
+	require.NoError(t, err)
+	require.Eventually(t, e1.IsLeader, time.Second, time.Millisecond)
+	_, err = e2.Run()
+	require.NoError(t, err)
+
+	// The leader renews its lease, so the follower can't take it over.
+	time.Sleep(400 * time.Millisecond)
+	assert.True(t, e1.IsLeader())
+	assert.False(t, e2.IsLeader())
+	assert.Equal(t, RoleLeader, e1.Health().HealthStatus(ctx).Tags[RoleTag])
+	assert.Equal(t, hexa.StatusReady, e1.Health().ReadinessStatus(ctx))
+	assert.Equal(t, RoleFollower, e2.Health().HealthStatus(ctx).Tags[RoleTag])
+	assert.Equal(t, hexa.StatusUnReady, e2.Health().ReadinessStatus(ctx))
+	assert.Equal(t, hexa.StatusAlive, e2.Health().LivenessStatus(ctx))
+
+	// The leader steps down on shutdown and the follower takes over.
+	require.NoError(t, e1.Shutdown(ctx))
+	assert.False(t, e1.IsLeader())
+	assert.Equal(t, int32(1), atomic.LoadInt32(&cb1.steppedDown))
+	require.Eventually(t, func() bool { return atomic.LoadInt32(&cb1.canceled) == 1 }, time.Second, time.Millisecond)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&cb1.elected))
+	require.Eventually(t, e2.IsLeader, time.Second, time.Millisecond)
+
+	require.NoError(t, e2.Shutdown(ctx))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&cb2.steppedDown))
+}
+
+// flakyDLM returns mutexes that fail to lock while fail is set.
+type flakyDLM struct {
+	hexa.DLM
+	fail int32
+}
+
+type flakyMutex struct {
+	hexa.Mutex
+	dlm *flakyDLM
+}
+
+func (d *flakyDLM) NewMutexWithOptions(o hexa.MutexOptions) hexa.Mutex {
+	return &flakyMutex{Mutex: d.DLM.NewMutexWithOptions(o), dlm: d}
+}
+
+func (m *flakyMutex) TryLock(ctx context.Context) error {
+	if atomic.LoadInt32(&m.dlm.fail) == 1 {
+		return hexa.ErrLockAlreadyAcquired
+	}
+	return m.Mutex.TryLock(ctx)
+}
+
+func TestElector_LostLease(t *testing.T) {
+	dlm, err := memlock.NewDlm(memlock.DlmOptions{})
+	require.NoError(t, err)
+	flaky := &flakyDLM{DLM: dlm}
+
+	var cb callbacks
+	e := newElector(t, flaky, "a", &cb)
+	_, err = e.Run()
+	require.NoError(t, err)
+	require.Eventually(t, e.IsLeader, time.Second, time.Millisecond)
+
+	// The renewal fails, e.g., because of a network partition.
+	atomic.StoreInt32(&flaky.fail, 1)
+	require.Eventually(t, func() bool { return !e.IsLeader() }, time.Second, time.Millisecond)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&cb.steppedDown))
+	require.Eventually(t, func() bool { return atomic.LoadInt32(&cb.canceled) == 1 }, time.Second, time.Millisecond)
+
+	// It becomes the leader again when it can renew the lease.
+	atomic.StoreInt32(&flaky.fail, 0)
+	require.Eventually(t, func() bool { return atomic.LoadInt32(&cb.elected) == 2 }, time.Second, time.Millisecond)
+	require.NoError(t, e.Shutdown(context.Background()))
+}
+
+func TestElector_ShutdownWithoutRun(t *testing.T) {
+	dlm, err := memlock.NewDlm(memlock.DlmOptions{})
+	require.NoError(t, err)
+
+	e := newElector(t, dlm, "a", &callbacks{})
+	require.NoError(t, e.Shutdown(context.Background()))
+
+	done, err := e.Run()
+	require.NoError(t, err)
+	_, ok := <-done
+	assert.False(t, ok, "run after shutdown must be done")
+}
+
+func TestElector_WithoutOwner(t *testing.T) {
+	// Mutexes without owner share the DLM's default owner.
+	dlm, err := memlock.NewDlm(memlock.DlmOptions{DefaultOwner: "app"})
+	require.NoError(t, err)
+
+	var cb1, cb2 callbacks
+	e1 := newElector(t, dlm, "", &cb1)
+	e2 := newElector(t, dlm, "", &cb2)
+	_, err = e1.Run()
+	require.NoError(t, err)
+	require.Eventually(t, e1.IsLeader, time.Second, time.Millisecond)
+	_, err = e2.Run()
+	require.NoError(t, err)
+
+	time.Sleep(400 * time.Millisecond)
+	assert.True(t, e1.IsLeader())
+	assert.False(t, e2.IsLeader(), "electors without owner must not share the leadership")
+	require.NoError(t, e1.Shutdown(context.Background()))
+	require.NoError(t, e2.Shutdown(context.Background()))
+}
+
+func TestNew_Validation(t *testing.T) {
+	_, err := New(Options{Key: "k"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,61 @@
+package leader
+
+import (
+	"context"
+
+	"github.com/kamva/hexa"
+)
+
+const (
+	RoleLeader   = "leader"
+	RoleFollower = "follower"
+)
+
+// RoleTag is the health status tag which reports the role of the instance.
+const RoleTag = "role"
+
+type health struct {
+	e *Elector
+}
+
+// Health returns a health which is always alive, but it's ready just when
+// this instance is the leader, e.g., to route traffic just to the leader.
+// Please note if you add it to the readiness checks of all services,
+// followers are not ready. Its status reports the role in the RoleTag tag.
+func (e *Elector) Health() hexa.Health {
+	return &health{e: e}
+}
+
+// Role returns the role of this instance.
+func (e *Elector) Role() string {
+	if e.IsLeader() {
+		return RoleLeader
+	}
+	return RoleFollower
+}
+
+func (h *health) HealthIdentifier() string {
+	return "leader_election:" + h.e.o.Key
+}
+
+func (h *health) LivenessStatus(context.Context) hexa.LivenessStatus {
+	return hexa.StatusAlive
+}
+
+func (h *health) ReadinessStatus(context.Context) hexa.ReadinessStatus {
+	if h.e.IsLeader() {
+		return hexa.StatusReady
+	}
+	return hexa.StatusUnReady
+}
+
+func (h *health) HealthStatus(ctx context.Context) hexa.HealthStatus {
+	return hexa.HealthStatus{
+		Id:    h.HealthIdentifier(),
+		Alive: h.LivenessStatus(ctx),
+		Ready: h.ReadinessStatus(ctx),
+		Tags:  map[string]string{RoleTag: h.e.Role()},
+	}
+}
+
+var _ hexa.Health = &health{}
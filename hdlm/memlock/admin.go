@@ -0,0 +1,49 @@
+package memlock
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kamva/hexa"
+	"github.com/kamva/hexa/hlog"
+)
+
+func (m *dlm) Locks(_ context.Context, prefix string) ([]hexa.LockInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	locks := make([]hexa.LockInfo, 0)
+	for key, l := range m.locks {
+		if strings.HasPrefix(key, prefix) && now.Before(l.expiry) {
+			locks = append(locks, hexa.LockInfo{Key: key, Owner: l.owner, Expiry: l.expiry})
+		}
+	}
+	sort.Slice(locks, func(i, j int) bool { return locks[i].Key < locks[j].Key })
+	return locks, nil
+}
+
+func (m *dlm) ForceUnlock(_ context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	l, ok := m.locks[key]
+	if !ok {
+		return nil
+	}
+	delete(m.locks, key)
+	m.notifyRelease()
+	hlog.Warn("force released the lock", hlog.String("key", key), hlog.String("owner", l.owner), hlog.Time("expiry", l.expiry))
+	return nil
+}
+
+func (m *dlm) ClearExpired(context.Context) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.clearExpired(time.Now()), nil
+}
+
+var _ hexa.LockAdmin = &dlm{}
@@ -0,0 +1,254 @@
+package memlock
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/kamva/hexa"
+	"github.com/kamva/hexa/hlog"
+	"github.com/kamva/tracer"
+)
+
+type DlmOptions struct {
+	// Default ttl value for a lock. e.g, 2s.
+	DefaultTTL time.Duration
+	// Default owner of locks. If it's empty too, each
+	// mutex is the owner of its own lock.
+	DefaultOwner string
+}
+
+// sweepInterval is the interval of removing expired locks.
+const sweepInterval = time.Minute
+
+// lock is a held lock and its fencing token.
+type lock struct {
+	owner  string
+	expiry time.Time
+	token  int64
+}
+
+// dlm implements the Hexa DLM.
+type dlm struct {
+	hexa.Health
+
+	owner string
+	ttl   time.Duration
+
+	mu    sync.Mutex
+	locks map[string]lock
+	// tokens is the last fencing token. Tokens of all keys share it, so
+	// they always increase even though we remove released locks.
+	tokens int64
+	// sweepAt is the next time that we remove expired locks.
+	sweepAt time.Time
+	// released is closed and replaced whenever a lock is released,
+	// so waiting mutexes can try again.
+	released chan struct{}
+	sems     map[string]map[string]time.Time // key -> owner -> expiry.
+	rws      map[string]*rwLock
+	// mutexes counts mutexes to generate owner of mutexes without owner.
+	mutexes uint64
+}
+
+func NewDlm(o DlmOptions) (hexa.DLM, error) {
+	dlm := &dlm{
+		Health: hexa.NewPingHealth(hlog.GlobalLogger(), "distributed_locks", func(context.Context) error {
+			return nil
+		}, nil),
+
+		owner:    o.DefaultOwner,
+		ttl:      o.DefaultTTL,
+		locks:    make(map[string]lock),
+		sems:     make(map[string]map[string]time.Time),
+		rws:      make(map[string]*rwLock),
+		released: make(chan struct{}),
+	}
+
+	return dlm, nil
+}
+
+func (m *dlm) NewMutex(Key string) hexa.Mutex {
+	return m.NewMutexWithOptions(hexa.MutexOptions{
+		Key:   Key,
+		Owner: m.owner,
+		TTL:   m.ttl,
+	})
+}
+
+func (m *dlm) NewMutexWithTTL(Key string, ttl time.Duration) hexa.Mutex {
+	return m.NewMutexWithOptions(hexa.MutexOptions{Key: Key, TTL: ttl})
+}
+
+func (m *dlm) NewMutexWithOptions(o hexa.MutexOptions) hexa.Mutex {
+	var mu hexa.Mutex = &mutex{
+		dlm: m,
+		ttl: o.TTL,
+
+		ID:    o.Key,
+		Owner: m.ownerOf(o.Owner),
+	}
+	if o.KeepAlive {
+		return hexa.NewKeepAliveMutex(mu, o.TTL)
+	}
+	return mu
+}
+
+// ownerOf returns the owner of a new mutex. If owner is empty, it returns
+// the default owner. If that's empty too, it gives the mutex its own
+// owner, just like redislock.
+func (m *dlm) ownerOf(owner string) string {
+	if owner == "" {
+		owner = m.owner
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if owner == "" {
+		m.mutexes++
+		owner = fmt.Sprintf("memlock-mutex-%d", m.mutexes)
+	}
+	return owner
+}
+
+// notifyRelease wakes up waiting mutexes. The caller must hold the lock of mu.
+func (m *dlm) notifyRelease() {
+	close(m.released)
+	m.released = make(chan struct{})
+}
+
+// wait waits until the expiry or until a lock is released.
+func wait(c context.Context, expiry time.Time, released <-chan struct{}) error {
+	t := time.NewTimer(time.Until(expiry))
+	defer t.Stop()
+	select {
+	case <-c.Done():
+		return c.Err()
+	case <-released:
+	case <-t.C:
+	}
+	return nil
+}
+
+// tryLock locks the key for the owner or refreshes its lock and returns
+// the lock's fencing token. If another owner holds the lock, it returns
+// the lock's expiry and a channel which is closed when a lock is released.
+func (m *dlm) tryLock(key string, owner string, ttl time.Duration) (int64, time.Time, <-chan struct{}, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	l, ok := m.locks[key]
+	if ok && l.owner != owner && now.Before(l.expiry) {
+		return 0, l.expiry, m.released, hexa.ErrLockAlreadyAcquired
+	}
+	m.sweep(now)
+
+	// The owner keeps its token on refresh, otherwise it's a new lock.
+	token := l.token
+	if !ok || l.owner != owner {
+		m.tokens++
+		token = m.tokens
+	}
+	m.locks[key] = lock{owner: owner, expiry: now.Add(ttl), token: token}
+	return token, time.Time{}, nil, nil
+}
+
+// sweep removes expired locks once in each sweep interval, so keys which
+// we don't lock again don't stay in memory. The caller must hold the lock
+// of mu.
+func (m *dlm) sweep(now time.Time) {
+	if now.Before(m.sweepAt) {
+		return
+	}
+	m.sweepAt = now.Add(sweepInterval)
+	m.clearExpired(now)
+}
+
+// clearExpired removes expired locks and returns their number. The caller
+// must hold the lock of mu.
+func (m *dlm) clearExpired(now time.Time) int64 {
+	var n int64
+	for key, l := range m.locks {
+		if !now.Before(l.expiry) {
+			delete(m.locks, key)
+			n++
+		}
+	}
+	return n
+}
+
+// unlock releases the owner's lock. It ignores the lock if it's
+// released or held by another owner.
+func (m *dlm) unlock(key string, owner string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if l, ok := m.locks[key]; !ok || l.owner != owner {
+		return
+	}
+	delete(m.locks, key)
+	m.notifyRelease()
+}
+
+// mutex implements hexa Mutex in memory.
+type mutex struct {
+	dlm *dlm
+	ttl time.Duration
+	// token is the fencing token of the current lock.
+	token int64
+
+	ID    string `json:"key"`
+	Owner string `json:"owner"`
+	// Expiry begins when we lock the mutex.
+	Expiry time.Time `json:"expiry"`
+}
+
+// Lock try to lock and if lock is held by another mutex, it waits
+// until the lock is released or expired and tries it again.
+func (m *mutex) Lock(c context.Context) error {
+	for {
+		token, expiry, released, err := m.dlm.tryLock(m.ID, m.Owner, m.ttl)
+		if err == nil {
+			m.token = token
+			m.Expiry = time.Now().Add(m.ttl)
+			return nil
+		}
+		m.token = 0
+
+		if err := wait(c, expiry, released); err != nil {
+			return err
+		}
+	}
+}
+
+func (m *mutex) TryLock(c context.Context) error {
+	if err := c.Err(); err != nil {
+		return tracer.Trace(err)
+	}
+
+	token, _, _, err := m.dlm.tryLock(m.ID, m.Owner, m.ttl)
+	if err != nil {
+		m.token = 0
+		return tracer.Trace(err)
+	}
+	m.token = token
+	m.Expiry = time.Now().Add(m.ttl)
+	return nil
+}
+
+func (m *mutex) Token() int64 {
+	return m.token
+}
+
+func (m *mutex) Unlock(context.Context) error {
+	m.dlm.unlock(m.ID, m.Owner)
+	m.token = 0
+	return nil
+}
+
+var _ hexa.DLM = &dlm{}
+var _ hexa.SemaphoreManager = &dlm{}
+var _ hexa.RWMutexManager = &dlm{}
+var _ hexa.FencedMutex = &mutex{}
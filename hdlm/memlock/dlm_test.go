@@ -0,0 +1,110 @@
+package memlock
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kamva/hexa"
+	"github.com/kamva/hexa/hdlm/dlmtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newDlm(t *testing.T, owner string) hexa.DLM {
+	t.Helper()
+	d, err := NewDlm(DlmOptions{DefaultOwner: owner, DefaultTTL: time.Minute})
+	require.NoError(t, err)
+	return d
+}
+
+func TestConformance(t *testing.T) {
+	dlmtest.Run(t, func(t *testing.T) hexa.DLM {
+		return newDlm(t, "")
+	})
+}
+
+func TestSemaphoreConformance(t *testing.T) {
+	dlmtest.RunSemaphore(t, func(t *testing.T) hexa.SemaphoreManager {
+		return newDlm(t, "").(hexa.SemaphoreManager)
+	})
+}
+
+func TestRWMutexConformance(t *testing.T) {
+	dlmtest.RunRWMutex(t, func(t *testing.T) hexa.RWMutexManager {
+		return newDlm(t, "").(hexa.RWMutexManager)
+	})
+}
+
+func TestLockAdminConformance(t *testing.T) {
+	dlmtest.RunLockAdmin(t, func(t *testing.T) dlmtest.Admin {
+		return newDlm(t, "").(dlmtest.Admin)
+	})
+}
+
+func TestClearExpired(t *testing.T) {
+	ctx := context.Background()
+	d := newDlm(t, "")
+	require.NoError(t, d.NewMutexWithTTL("expired", time.Millisecond).TryLock(ctx))
+	require.NoError(t, d.NewMutexWithTTL("held", time.Minute).TryLock(ctx))
+	time.Sleep(10 * time.Millisecond)
+
+	n, err := d.(hexa.LockAdmin).ClearExpired(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+}
+
+func TestDlm_RemovesReleasedAndExpiredLocks(t *testing.T) {
+	ctx := context.Background()
+	d := newDlm(t, "").(*dlm)
+
+	m := d.NewMutex("released")
+	require.NoError(t, m.TryLock(ctx))
+	token := m.(hexa.FencedMutex).Token()
+	require.NoError(t, m.Unlock(ctx))
+	assert.Empty(t, d.locks)
+
+	require.NoError(t, d.NewMutexWithTTL("expired", time.Millisecond).TryLock(ctx))
+	time.Sleep(10 * time.Millisecond)
+	d.sweepAt = time.Time{} // don't wait for the sweep interval.
+	require.NoError(t, m.TryLock(ctx))
+	assert.Len(t, d.locks, 1)
+	assert.Greater(t, m.(hexa.FencedMutex).Token(), token, "tokens must increase after removing locks")
+}
+
+func TestMutex_EmptyOwner(t *testing.T) {
+	ctx := context.Background()
+	d := newDlm(t, "")
+
+	require.NoError(t, d.NewMutex("key").TryLock(ctx))
+	assert.ErrorIs(t, d.NewMutex("key").TryLock(ctx), hexa.ErrLockAlreadyAcquired)
+}
+
+func TestNewDlm_Health(t *testing.T) {
+	h, ok := newDlm(t, "").(hexa.Health)
+	require.True(t, ok)
+	assert.Equal(t, "distributed_locks", h.HealthIdentifier())
+	assert.Equal(t, hexa.StatusAlive, h.LivenessStatus(context.Background()))
+}
+
+func TestMutex_KeepAlive(t *testing.T) {
+	ctx := context.Background()
+	d := newDlm(t, "")
+	m := d.NewMutexWithOptions(hexa.MutexOptions{Key: "key", TTL: 60 * time.Millisecond, KeepAlive: true})
+	other := d.NewMutex("key")
+
+	require.NoError(t, m.Lock(ctx))
+	time.Sleep(150 * time.Millisecond) // longer than the ttl.
+	assert.ErrorIs(t, other.TryLock(ctx), hexa.ErrLockAlreadyAcquired)
+
+	km, ok := m.(hexa.KeepAliveMutex)
+	require.True(t, ok)
+	select {
+	case <-km.Lost():
+		t.Fatal("renewed lock must not be lost")
+	default:
+	}
+
+	require.NoError(t, m.Unlock(ctx))
+	require.NoError(t, other.TryLock(ctx))
+}
@@ -0,0 +1,4 @@
+// Package memlock implements hexa's distributed lock manager (hexa.DLM) in
+// memory. Locks are shared just between mutexes of the same DLM, so it's
+// useful for tests and single-instance apps, e.g., CLI tools.
+package memlock
@@ -0,0 +1,167 @@
+package memlock
+
+import (
+	"context"
+	"time"
+
+	"github.com/kamva/hexa"
+	"github.com/kamva/tracer"
+)
+
+// rwLock is a read/write lock of a key.
+type rwLock struct {
+	writer  lock
+	readers map[string]time.Time // owner -> expiry.
+}
+
+// clean removes the expired locks and returns the first expiry of the
+// remained locks.
+func (l *rwLock) clean(now time.Time) time.Time {
+	var first time.Time
+	if l.writer.owner != "" {
+		if now.Before(l.writer.expiry) {
+			first = l.writer.expiry
+		} else {
+			l.writer = lock{}
+		}
+	}
+
+	for o, expiry := range l.readers {
+		if !now.Before(expiry) {
+			delete(l.readers, o)
+			continue
+		}
+		if first.IsZero() || expiry.Before(first) {
+			first = expiry
+		}
+	}
+	return first
+}
+
+func (m *dlm) NewRWMutex(o hexa.MutexOptions) hexa.RWMutex {
+	return &rwMutex{
+		dlm:   m,
+		ttl:   o.TTL,
+		key:   o.Key,
+		owner: m.ownerOf(o.Owner),
+	}
+}
+
+// tryRWLock read or write locks the key for the owner or refreshes its
+// lock. If it can not lock, it returns the first expiry of the current
+// locks and a channel which is closed when a lock is released.
+func (m *dlm) tryRWLock(key string, owner string, ttl time.Duration, write bool) (time.Time, <-chan struct{}, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	l := m.rws[key]
+	if l == nil {
+		l = &rwLock{readers: make(map[string]time.Time)}
+		m.rws[key] = l
+	}
+	first := l.clean(now)
+
+	if l.writer.owner != "" && l.writer.owner != owner {
+		return first, m.released, hexa.ErrLockAlreadyAcquired
+	}
+
+	if !write {
+		l.readers[owner] = now.Add(ttl)
+		return time.Time{}, nil, nil
+	}
+
+	for o := range l.readers {
+		if o != owner {
+			return first, m.released, hexa.ErrLockAlreadyAcquired
+		}
+	}
+	l.writer = lock{owner: owner, expiry: now.Add(ttl)}
+	return time.Time{}, nil, nil
+}
+
+// rwUnlock releases the owner's read or write lock.
+func (m *dlm) rwUnlock(key string, owner string, write bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	l := m.rws[key]
+	if l == nil {
+		return
+	}
+
+	if write {
+		if l.writer.owner != owner {
+			return
+		}
+		l.writer = lock{}
+	} else {
+		if _, ok := l.readers[owner]; !ok {
+			return
+		}
+		delete(l.readers, owner)
+	}
+
+	if l.writer.owner == "" && len(l.readers) == 0 {
+		delete(m.rws, key)
+	}
+	m.notifyRelease()
+}
+
+// rwMutex implements hexa RWMutex in memory.
+type rwMutex struct {
+	dlm   *dlm
+	ttl   time.Duration
+	key   string
+	owner string
+}
+
+func (m *rwMutex) lock(c context.Context, write bool) error {
+	for {
+		expiry, released, err := m.dlm.tryRWLock(m.key, m.owner, m.ttl, write)
+		if err == nil {
+			return nil
+		}
+
+		if err := wait(c, expiry, released); err != nil {
+			return err
+		}
+	}
+}
+
+func (m *rwMutex) tryLock(c context.Context, write bool) error {
+	if err := c.Err(); err != nil {
+		return tracer.Trace(err)
+	}
+
+	_, _, err := m.dlm.tryRWLock(m.key, m.owner, m.ttl, write)
+	return tracer.Trace(err)
+}
+
+func (m *rwMutex) Lock(c context.Context) error {
+	return m.lock(c, true)
+}
+
+func (m *rwMutex) TryLock(c context.Context) error {
+	return m.tryLock(c, true)
+}
+
+func (m *rwMutex) Unlock(context.Context) error {
+	m.dlm.rwUnlock(m.key, m.owner, true)
+	return nil
+}
+
+func (m *rwMutex) RLock(c context.Context) error {
+	return m.lock(c, false)
+}
+
+func (m *rwMutex) TryRLock(c context.Context) error {
+	return m.tryLock(c, false)
+}
+
+func (m *rwMutex) RUnlock(context.Context) error {
+	m.dlm.rwUnlock(m.key, m.owner, false)
+	return nil
+}
+
+var _ hexa.RWMutex = &rwMutex{}
@@ -0,0 +1,107 @@
+package memlock
+
+import (
+	"context"
+	"time"
+
+	"github.com/kamva/hexa"
+	"github.com/kamva/tracer"
+)
+
+func (m *dlm) NewSemaphore(o hexa.SemaphoreOptions) hexa.Semaphore {
+	if o.Permits < 1 {
+		o.Permits = 1
+	}
+
+	return &semaphore{
+		dlm:     m,
+		ttl:     o.TTL,
+		permits: o.Permits,
+		key:     o.Key,
+		owner:   m.ownerOf(o.Owner),
+	}
+}
+
+// tryAcquire acquires or refreshes the owner's permit. If all permits
+// are held, it returns the first expiry of the permits and a channel
+// which is closed when a lock is released.
+func (m *dlm) tryAcquire(key string, owner string, permits int, ttl time.Duration) (time.Time, <-chan struct{}, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	holders := m.sems[key]
+	if holders == nil {
+		holders = make(map[string]time.Time)
+		m.sems[key] = holders
+	}
+
+	var first time.Time
+	for o, expiry := range holders {
+		if !now.Before(expiry) {
+			delete(holders, o)
+			continue
+		}
+		if first.IsZero() || expiry.Before(first) {
+			first = expiry
+		}
+	}
+
+	if _, ok := holders[owner]; !ok && len(holders) >= permits {
+		return first, m.released, hexa.ErrLockAlreadyAcquired
+	}
+	holders[owner] = now.Add(ttl)
+	return time.Time{}, nil, nil
+}
+
+func (m *dlm) release(key string, owner string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.sems[key][owner]; !ok {
+		return
+	}
+	delete(m.sems[key], owner)
+	if len(m.sems[key]) == 0 {
+		delete(m.sems, key)
+	}
+	m.notifyRelease()
+}
+
+// semaphore implements hexa Semaphore in memory.
+type semaphore struct {
+	dlm     *dlm
+	ttl     time.Duration
+	permits int
+	key     string
+	owner   string
+}
+
+func (s *semaphore) Acquire(c context.Context) error {
+	for {
+		expiry, released, err := s.dlm.tryAcquire(s.key, s.owner, s.permits, s.ttl)
+		if err == nil {
+			return nil
+		}
+
+		if err := wait(c, expiry, released); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *semaphore) TryAcquire(c context.Context) error {
+	if err := c.Err(); err != nil {
+		return tracer.Trace(err)
+	}
+
+	_, _, err := s.dlm.tryAcquire(s.key, s.owner, s.permits, s.ttl)
+	return tracer.Trace(err)
+}
+
+func (s *semaphore) Release(context.Context) error {
+	s.dlm.release(s.key, s.owner)
+	return nil
+}
+
+var _ hexa.Semaphore = &semaphore{}
@@ -0,0 +1,67 @@
+package mongolock
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"time"
+
+	"github.com/kamva/hexa"
+	"github.com/kamva/hexa/hlog"
+	"github.com/kamva/tracer"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func (m *dlm) Locks(c context.Context, prefix string) ([]hexa.LockInfo, error) {
+	filter := bson.M{
+		"_id":    primitive.Regex{Pattern: "^" + regexp.QuoteMeta(prefix)},
+		"expiry": bson.M{"$gt": time.Now()},
+	}
+	cur, err := m.coll.Find(c, filter, options.Find().SetSort(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, tracer.Trace(err)
+	}
+
+	var docs []mutex
+	if err := cur.All(c, &docs); err != nil {
+		return nil, tracer.Trace(err)
+	}
+
+	locks := make([]hexa.LockInfo, len(docs))
+	for i, doc := range docs {
+		locks[i] = hexa.LockInfo{Key: doc.ID, Owner: doc.Owner, Expiry: doc.Expiry}
+	}
+	return locks, nil
+}
+
+func (m *dlm) ForceUnlock(c context.Context, key string) error {
+	var doc mutex
+	err := m.coll.FindOneAndDelete(c, bson.M{"_id": key}).Decode(&doc)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil
+	}
+	if err != nil {
+		return tracer.Trace(err)
+	}
+	if err = releaseFence(c, m.fencingColl, key, doc.Owner); err != nil {
+		return tracer.Trace(err)
+	}
+
+	hlog.Warn("force released the lock", hlog.String("key", key), hlog.String("owner", doc.Owner), hlog.Time("expiry", doc.Expiry))
+	return nil
+}
+
+// ClearExpired removes the expired locks. The TTL index removes them too,
+// but MongoDB runs it just once a minute.
+func (m *dlm) ClearExpired(c context.Context) (int64, error) {
+	res, err := m.coll.DeleteMany(c, bson.M{"expiry": bson.M{"$lt": time.Now()}})
+	if err != nil {
+		return 0, tracer.Trace(err)
+	}
+	return res.DeletedCount, nil
+}
+
+var _ hexa.LockAdmin = &dlm{}
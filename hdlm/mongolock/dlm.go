@@ -2,12 +2,15 @@ package mongolock
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/base64"
 	"errors"
 	"time"
 
 	"github.com/kamva/gutil"
 	"github.com/kamva/hexa"
 	"github.com/kamva/hexa/db/mgmadapter"
+	"github.com/kamva/hexa/hdlm/internal/lockwait"
 	"github.com/kamva/hexa/hlog"
 	"github.com/kamva/tracer"
 	"go.mongodb.org/mongo-driver/bson"
@@ -18,11 +21,41 @@ import (
 // CollectionName is default collection name.
 const CollectionName = "locks"
 
+// FencingCollectionSuffix is the suffix of the default fencing collection
+// name, e.g., locks_fencing.
+const FencingCollectionSuffix = "_fencing"
+
+// Suffixes of the default semaphores and read/write locks collection names,
+// e.g., locks_semaphores.
+const (
+	SemaphoreCollectionSuffix = "_semaphores"
+	RWMutexCollectionSuffix   = "_rwlocks"
+)
+
 type DlmOptions struct {
 	Collection *mongo.Collection
+	// FencingCollection keeps fencing tokens of locks and their holders.
+	// its documents never expire, so tokens always increase. Default value is the Collection's
+	// name with the FencingCollectionSuffix.
+	FencingCollection *mongo.Collection
+	// SemaphoreCollection keeps semaphores. Default value is the
+	// Collection's name with the SemaphoreCollectionSuffix.
+	SemaphoreCollection *mongo.Collection
+	// RWMutexCollection keeps read/write locks. Default value is the
+	// Collection's name with the RWMutexCollectionSuffix.
+	RWMutexCollection *mongo.Collection
 	// If a lock already held by another mutex, we need to interval
 	// to check it again.
 	WaitingInterval time.Duration
+	// MaxWaitingInterval enables exponential backoff: the waiting interval
+	// doubles on each try up to this value. zero means a fixed interval.
+	MaxWaitingInterval time.Duration
+	// WaitingJitter randomizes each waiting interval by up to this
+	// fraction of it, e.g., 0.2 means ±20%.
+	WaitingJitter float64
+	// MaxWait limits the time that Lock waits for a lock, then it returns
+	// the hexa.ErrLockAlreadyAcquired error. zero means no limit.
+	MaxWait time.Duration
 	// Default ttl value for a lock. e.g, 2s.
 	DefaultTTL time.Duration
 	// usually your machine name. e.g., k8s-pod-199831uf.
@@ -33,36 +66,85 @@ type DlmOptions struct {
 type dlm struct {
 	hexa.Health
 
-	coll *mongo.Collection
+	coll          *mongo.Collection
+	fencingColl   *mongo.Collection
+	semaphoreColl *mongo.Collection
+	rwColl        *mongo.Collection
 	// owner is default lock owner.
 	owner string
 	// ttl is default lock ttl.
 	ttl time.Duration
-	// interval is waiting time before try to lock again if
-	// lock already held by another mutex.
-	interval time.Duration
+	// wait is how to wait before try to lock again if lock
+	// already held by another mutex.
+	wait lockwait.Options
 }
 
 func NewDlm(o DlmOptions) (hexa.DLM, error) {
+	db := o.Collection.Database()
+	if o.FencingCollection == nil {
+		o.FencingCollection = db.Collection(o.Collection.Name() + FencingCollectionSuffix)
+	}
+	if o.SemaphoreCollection == nil {
+		o.SemaphoreCollection = db.Collection(o.Collection.Name() + SemaphoreCollectionSuffix)
+	}
+	if o.RWMutexCollection == nil {
+		o.RWMutexCollection = db.Collection(o.Collection.Name() + RWMutexCollectionSuffix)
+	}
+
 	dlm := &dlm{
 		Health: hexa.NewPingHealth(hlog.GlobalLogger(), "distributed_locks", mgmadapter.HealthPing(o.Collection.Database().Client()), nil),
 
-		coll:     o.Collection,
-		ttl:      o.DefaultTTL,
-		owner:    o.DefaultOwner,
-		interval: o.WaitingInterval,
+		coll:          o.Collection,
+		fencingColl:   o.FencingCollection,
+		semaphoreColl: o.SemaphoreCollection,
+		rwColl:        o.RWMutexCollection,
+		ttl:           o.DefaultTTL,
+		owner:         o.DefaultOwner,
+		wait: lockwait.Options{
+			Interval:    o.WaitingInterval,
+			MaxInterval: o.MaxWaitingInterval,
+			Jitter:      o.WaitingJitter,
+			MaxWait:     o.MaxWait,
+		},
 	}
 
 	return dlm, tracer.Trace(dlm.createIndexesIfNotExist())
 }
 
+// indexOptionsConflict is the MongoDB error code of creating an index which
+// exists with different options.
+const indexOptionsConflict = 85
+
 func (m *dlm) createIndexesIfNotExist() error {
+	for _, coll := range []*mongo.Collection{m.coll, m.semaphoreColl, m.rwColl} {
+		if err := createTTLIndex(coll); err != nil {
+			return tracer.Trace(err)
+		}
+	}
+	return nil
+}
+
+// createTTLIndex creates the TTL index of the expiry field of the collection.
+func createTTLIndex(coll *mongo.Collection) error {
 	// Please note this index doesn't have any effect on the mutex behavior,
-	// its just for cleanup.
-	_, err := m.coll.Indexes().CreateOne(context.Background(), mongo.IndexModel{
-		Keys:    bson.D{bson.E{Key: "expiry", Value: 1}},
-		Options: &options.IndexOptions{Name: gutil.NewString("expired_locks")},
-	})
+	// its just for cleanup: MongoDB removes expired locks using this TTL index.
+	model := mongo.IndexModel{
+		Keys: bson.D{bson.E{Key: "expiry", Value: 1}},
+		Options: &options.IndexOptions{
+			Name:               gutil.NewString("expired_locks"),
+			ExpireAfterSeconds: gutil.NewInt32(0),
+		},
+	}
+	_, err := coll.Indexes().CreateOne(context.Background(), model)
+
+	// Older versions created this index without TTL, so replace it.
+	var cmdErr mongo.CommandError
+	if errors.As(err, &cmdErr) && cmdErr.Code == indexOptionsConflict {
+		if _, err = coll.Indexes().DropOne(context.Background(), *model.Options.Name); err != nil {
+			return tracer.Trace(err)
+		}
+		_, err = coll.Indexes().CreateOne(context.Background(), model)
+	}
 	return tracer.Trace(err)
 }
 
@@ -79,18 +161,38 @@ func (m *dlm) NewMutexWithTTL(Key string, ttl time.Duration) hexa.Mutex {
 }
 
 func (m *dlm) NewMutexWithOptions(o hexa.MutexOptions) hexa.Mutex {
-	if o.Owner == "" {
-		o.Owner = m.owner
-	}
+	o.Owner = m.ownerOf(o.Owner)
 
-	return &mutex{
-		coll:     m.coll,
-		ttl:      o.TTL,
-		interval: m.interval,
+	var mu hexa.Mutex = &mutex{
+		coll:        m.coll,
+		fencingColl: m.fencingColl,
+		ttl:         o.TTL,
+		wait:        m.wait,
 
 		ID:    o.Key,
 		Owner: o.Owner,
 	}
+	if o.KeepAlive {
+		return hexa.NewKeepAliveMutex(mu, o.TTL)
+	}
+	return mu
+}
+
+// ownerOf returns the owner of a new mutex, semaphore or read/write lock.
+// If owner is empty, it returns the default owner. If that's empty too, it
+// returns a random owner, so they don't share their locks, just like
+// redislock.
+func (m *dlm) ownerOf(owner string) string {
+	if owner == "" {
+		owner = m.owner
+	}
+	if owner != "" {
+		return owner
+	}
+
+	b := make([]byte, 16)
+	_, _ = rand.Read(b) // it never returns an error.
+	return base64.RawURLEncoding.EncodeToString(b)
 }
 
 // mutex implements hexa Mutex distributed lock using MongoDB.
@@ -99,12 +201,15 @@ func (m *dlm) NewMutexWithOptions(o hexa.MutexOptions) hexa.Mutex {
 // this query will get update my key or if key is expired, otherwise try to insert new key which
 // either create the new key or get duplicate key error which means key held by another mutex.
 type mutex struct {
-	coll *mongo.Collection
-	ttl  time.Duration
+	coll        *mongo.Collection
+	fencingColl *mongo.Collection
+	ttl         time.Duration
+	// token is the fencing token of the current lock.
+	token int64
 
-	// interval is waiting time before try to lock again if
-	// lock already held by another mutex.
-	interval time.Duration
+	// wait is how to wait before try to lock again if lock
+	// already held by another mutex.
+	wait lockwait.Options
 
 	ID    string `json:"key" bson:"_id"`
 	Owner string `json:"owner" bson:"owner"`
@@ -115,19 +220,7 @@ type mutex struct {
 // Lock try to lock and if lock is held by another mutex, it wait and
 // try it again.
 func (m *mutex) Lock(c context.Context) error {
-	for {
-		err := m.TryLock(c)
-
-		if !errors.Is(err, hexa.ErrLockAlreadyAcquired) {
-			return tracer.Trace(err)
-		}
-
-		select {
-		case <-c.Done():
-			return c.Err()
-		case <-time.After(m.interval):
-		}
-	}
+	return tracer.Trace(lockwait.Wait(c, m.wait, m.TryLock, nil))
 }
 
 func (m *mutex) TryLock(c context.Context) error {
@@ -145,14 +238,87 @@ func (m *mutex) TryLock(c context.Context) error {
 	if mongo.IsDuplicateKeyError(err) {
 		err = hexa.ErrLockAlreadyAcquired
 	}
+	if err != nil {
+		m.token = 0
+		return tracer.Trace(err)
+	}
+
+	if err = m.fence(c); err != nil {
+		// Don't keep the lock that we can't fence, otherwise it stays until
+		// its expiry, as the caller doesn't unlock it.
+		_, _ = m.coll.DeleteOne(c, bson.M{"_id": m.ID, "owner": m.Owner})
+		return tracer.Trace(err)
+	}
+	return nil
+}
+
+// fence sets the fencing token of the lock. It increases the token
+// if the lock has a new owner. The fencing document mirrors the lock's
+// owner and expiry, so an owner can't fence while another owner holds
+// the lock, e.g., when our lock expires between the lock and the fence.
+func (m *mutex) fence(c context.Context) error {
+	filter := bson.M{"_id": m.ID, "$or": bson.A{
+		bson.M{"holder": m.Owner},
+		bson.M{"expiry": bson.M{"$lt": time.Now()}},
+		bson.M{"expiry": bson.M{"$exists": false}},
+	}}
+	update := bson.A{bson.M{"$set": bson.M{
+		"token": bson.M{"$cond": bson.A{
+			bson.M{"$eq": bson.A{"$holder", literal(m.Owner)}},
+			"$token",
+			bson.M{"$add": bson.A{bson.M{"$ifNull": bson.A{"$token", 0}}, 1}},
+		}},
+		"holder": literal(m.Owner),
+		"expiry": m.Expiry,
+	}}}
+
+	var res struct {
+		Token int64 `bson:"token"`
+	}
+	err := m.fencingColl.FindOneAndUpdate(c, filter, update, options.FindOneAndUpdate().
+		SetUpsert(true).
+		SetReturnDocument(options.After)).Decode(&res)
+	if mongo.IsDuplicateKeyError(err) {
+		err = hexa.ErrLockAlreadyAcquired
+	}
+	if err != nil {
+		m.token = 0
+		return tracer.Trace(err)
+	}
 
+	m.token = res.Token
+	return nil
+}
+
+// releaseFence releases the fencing document of the owner's lock, so
+// other owners can fence before its expiry.
+func releaseFence(c context.Context, coll *mongo.Collection, key string, owner string) error {
+	_, err := coll.UpdateOne(c, bson.M{"_id": key, "holder": owner}, bson.M{"$unset": bson.M{"holder": "", "expiry": ""}})
 	return tracer.Trace(err)
 }
 
+// literal prevents evaluation of a value in aggregation pipelines,
+// e.g., an owner which begins with $.
+func literal(v any) bson.M {
+	return bson.M{"$literal": v}
+}
+
+func (m *mutex) Token() int64 {
+	return m.token
+}
+
 func (m *mutex) Unlock(c context.Context) error {
+	// Release the fence first, so the lock is held until both are released.
+	if err := releaseFence(c, m.fencingColl, m.ID, m.Owner); err != nil {
+		return tracer.Trace(err)
+	}
 	_, err := m.coll.DeleteOne(c, bson.M{"_id": m.ID, "owner": m.Owner})
-	return tracer.Trace(err)
+	if err != nil {
+		return tracer.Trace(err)
+	}
+	m.token = 0
+	return nil
 }
 
 var _ hexa.DLM = &dlm{}
-var _ hexa.Mutex = &mutex{}
+var _ hexa.FencedMutex = &mutex{}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/kamva/gutil"
 	"github.com/kamva/hexa"
+	"github.com/kamva/hexa/hdlm/dlmtest"
 	"github.com/kamva/hexa/hexatranslator"
 	"github.com/kamva/hexa/hlog"
 	"github.com/kamva/mgm/v3"
@@ -91,8 +92,7 @@ func TestNewDlmDatabaseIndex(t *testing.T) {
 	_, err = collection.Indexes().CreateOne(context.Background(), mongo.IndexModel{
 		Keys: bson.D{bson.E{Key: "expiry", Value: 1}},
 		Options: &options.IndexOptions{
-			ExpireAfterSeconds: gutil.NewInt32(0),
-			Name:               gutil.NewString("expired_locks"),
+			Name: gutil.NewString("expired_locks"),
 		},
 	})
 
@@ -101,13 +101,67 @@ func TestNewDlmDatabaseIndex(t *testing.T) {
 	_, err = collection.Indexes().CreateOne(context.Background(), mongo.IndexModel{
 		Keys: bson.D{bson.E{Key: "expiry", Value: 1}},
 		Options: &options.IndexOptions{
-			Name: gutil.NewString("expired_locks"),
+			ExpireAfterSeconds: gutil.NewInt32(0),
+			Name:               gutil.NewString("expired_locks"),
 		},
 	})
 
 	require.Nil(t, err)
 }
 
+func TestNewDlmDatabaseIndex_SemaphoresAndRWLocks(t *testing.T) {
+	setupDefConnection(t)
+	defer disconnect()
+
+	db := collection.Database()
+	for _, name := range []string{CollectionName + SemaphoreCollectionSuffix, CollectionName + RWMutexCollectionSuffix} {
+		gutil.PanicErr(db.Collection(name).Drop(mgm.Ctx()))
+	}
+	_, err := NewDlm(DlmOptions{Collection: collection, DefaultTTL: time.Minute})
+	require.NoError(t, err)
+
+	for _, name := range []string{CollectionName + SemaphoreCollectionSuffix, CollectionName + RWMutexCollectionSuffix} {
+		cur, listErr := db.Collection(name).Indexes().List(mgm.Ctx())
+		require.NoError(t, listErr)
+		var indexes []bson.M
+		require.NoError(t, cur.All(mgm.Ctx(), &indexes))
+
+		var ttl bool
+		for _, index := range indexes {
+			if index["name"] == "expired_locks" {
+				_, ttl = index["expireAfterSeconds"]
+			}
+		}
+		assert.True(t, ttl, "%s must have the TTL index", name)
+	}
+}
+
+func TestNewDlmDatabaseIndex_ReplacesIndexWithoutTTL(t *testing.T) {
+	setupDefConnection(t)
+	defer disconnect()
+
+	resetCollection()
+	gutil.PanicErr(collection.Drop(mgm.Ctx()))
+	// Older versions created the index without TTL.
+	_, err := collection.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys:    bson.D{bson.E{Key: "expiry", Value: 1}},
+		Options: &options.IndexOptions{Name: gutil.NewString("expired_locks")},
+	})
+	require.Nil(t, err)
+
+	_, err = NewDlm(DlmOptions{Collection: collection, DefaultTTL: time.Second * 60})
+	require.Nil(t, err)
+
+	_, err = collection.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys: bson.D{bson.E{Key: "expiry", Value: 1}},
+		Options: &options.IndexOptions{
+			ExpireAfterSeconds: gutil.NewInt32(0),
+			Name:               gutil.NewString("expired_locks"),
+		},
+	})
+	require.Nil(t, err)
+}
+
 func TestDlm_NewMutex(t *testing.T) {
 	setupDefConnection(t)
 	defer disconnect()
@@ -407,3 +461,75 @@ func TestMutex_UnlockBeforeExpiration(t *testing.T) {
 	// we should acquire lock after releaseAfter time elapsed.
 	assert.True(t, time.Since(start) > releaseAfter)
 }
+
+func TestConformance(t *testing.T) {
+	setupDefConnection(t)
+	defer disconnect()
+
+	resetCollection()
+	newDlm := func(t *testing.T) hexa.DLM {
+		d, err := NewDlm(DlmOptions{
+			Collection:      collection,
+			WaitingInterval: 20 * time.Millisecond,
+			DefaultTTL:      time.Minute,
+		})
+		require.NoError(t, err)
+		return d
+	}
+
+	dlmtest.Run(t, newDlm)
+	dlmtest.RunSemaphore(t, func(t *testing.T) hexa.SemaphoreManager { return newDlm(t).(hexa.SemaphoreManager) })
+	dlmtest.RunRWMutex(t, func(t *testing.T) hexa.RWMutexManager { return newDlm(t).(hexa.RWMutexManager) })
+	dlmtest.RunLockAdmin(t, func(t *testing.T) dlmtest.Admin { return newDlm(t).(dlmtest.Admin) })
+}
+
+// TestMutex_StaleFence checks the fence of a lock which is expired between
+// the lock and the fence doesn't get a token while another owner holds it.
+func TestMutex_StaleFence(t *testing.T) {
+	setupDefConnection(t)
+	defer disconnect()
+	resetCollection()
+
+	ctx := context.Background()
+	d, err := NewDlm(DlmOptions{Collection: collection, DefaultTTL: time.Minute})
+	require.NoError(t, err)
+	key := "stale-" + time.Now().Format(time.RFC3339Nano) // fences are not reset.
+	m1 := d.NewMutexWithOptions(hexa.MutexOptions{Key: key, Owner: "owner-1", TTL: 50 * time.Millisecond}).(*mutex)
+	m2 := d.NewMutexWithOptions(hexa.MutexOptions{Key: key, Owner: "owner-2", TTL: time.Minute}).(*mutex)
+
+	require.NoError(t, m1.TryLock(ctx))
+	time.Sleep(100 * time.Millisecond)
+	require.NoError(t, m2.TryLock(ctx))
+	t2 := m2.Token()
+
+	assert.ErrorIs(t, m1.fence(ctx), hexa.ErrLockAlreadyAcquired)
+	assert.Zero(t, m1.Token())
+	require.NoError(t, m2.TryLock(ctx))
+	assert.Equal(t, t2, m2.Token())
+}
+
+func TestMutex_FenceFailureReleasesLock(t *testing.T) {
+	setupDefConnection(t)
+	defer disconnect()
+	resetCollection()
+
+	ctx := context.Background()
+	d, err := NewDlm(DlmOptions{Collection: collection, DefaultTTL: time.Minute})
+	require.NoError(t, err)
+	key := "unfenced-" + time.Now().Format(time.RFC3339Nano) // fences are not reset.
+	m1 := d.NewMutexWithOptions(hexa.MutexOptions{Key: key, Owner: "owner-1", TTL: 50 * time.Millisecond}).(*mutex)
+	m2 := d.NewMutexWithOptions(hexa.MutexOptions{Key: key, Owner: "owner-2", TTL: time.Minute}).(*mutex)
+
+	require.NoError(t, m1.TryLock(ctx))
+	time.Sleep(100 * time.Millisecond)
+	require.NoError(t, m2.TryLock(ctx))
+
+	// m2's fence is held, so m1 can't fence it after taking its lock.
+	_, err = m2.coll.DeleteOne(ctx, bson.M{"_id": key})
+	require.NoError(t, err)
+	assert.ErrorIs(t, m1.TryLock(ctx), hexa.ErrLockAlreadyAcquired)
+
+	n, err := m1.coll.CountDocuments(ctx, bson.M{"_id": key})
+	require.NoError(t, err)
+	assert.Zero(t, n, "the unfenced lock must be released")
+}
@@ -0,0 +1,130 @@
+package mongolock
+
+import (
+	"context"
+	"time"
+
+	"github.com/kamva/hexa"
+	"github.com/kamva/hexa/hdlm/internal/lockwait"
+	"github.com/kamva/tracer"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func (m *dlm) NewRWMutex(o hexa.MutexOptions) hexa.RWMutex {
+	o.Owner = m.ownerOf(o.Owner)
+
+	return &rwMutex{
+		coll:  m.rwColl,
+		ttl:   o.TTL,
+		wait:  m.wait,
+		key:   o.Key,
+		owner: o.Owner,
+	}
+}
+
+// rwMutex implements hexa RWMutex using a document that keeps its writer,
+// readers and their last expiry:
+// {_id: "key", writer: {owner: "me", expiry: date}, readers: [...], expiry: date}
+type rwMutex struct {
+	coll  *mongo.Collection
+	ttl   time.Duration
+	wait  lockwait.Options
+	key   string
+	owner string
+}
+
+type rwDoc struct {
+	Writer  *holder  `bson:"writer"`
+	Readers []holder `bson:"readers"`
+}
+
+// tryLock removes the expired writer and readers, then it runs the stage
+// to lock and returns the updated document.
+func (m *rwMutex) tryLock(c context.Context, stage bson.M) (*rwDoc, error) {
+	now := time.Now()
+	update := bson.A{
+		bson.M{"$set": bson.M{
+			"writer":  bson.M{"$cond": bson.A{bson.M{"$gt": bson.A{"$writer.expiry", now}}, "$writer", nil}},
+			"readers": unexpired(bson.M{"$ifNull": bson.A{"$readers", bson.A{}}}, now),
+		}},
+		stage,
+		expiryStage(bson.M{"$max": bson.A{"$writer.expiry", bson.M{"$max": "$readers.expiry"}}}, now),
+	}
+
+	var doc rwDoc
+	if err := findOneAndUpsert(c, m.coll, m.key, update, &doc); err != nil {
+		return nil, tracer.Trace(err)
+	}
+	return &doc, nil
+}
+
+// writable returns an expression which is true when there is no
+// writer or the owner is the writer.
+func writable(owner bson.M) bson.M {
+	return bson.M{"$or": bson.A{
+		bson.M{"$eq": bson.A{"$writer", nil}},
+		bson.M{"$eq": bson.A{"$writer.owner", owner}},
+	}}
+}
+
+func (m *rwMutex) Lock(c context.Context) error {
+	return tracer.Trace(lockwait.Wait(c, m.wait, m.TryLock, nil))
+}
+
+// TryLock sets the owner as the writer if the lock is writable and
+// the owner is the only reader, if any.
+func (m *rwMutex) TryLock(c context.Context) error {
+	owner := literal(m.owner)
+	doc, err := m.tryLock(c, bson.M{"$set": bson.M{"writer": bson.M{"$cond": bson.A{
+		bson.M{"$and": bson.A{
+			writable(owner),
+			bson.M{"$eq": bson.A{bson.M{"$size": withoutHolder("$readers", owner)}, 0}},
+		}},
+		bson.M{"owner": owner, "expiry": time.Now().Add(m.ttl)},
+		"$writer",
+	}}}})
+	if err != nil {
+		return tracer.Trace(err)
+	}
+
+	if doc.Writer == nil || doc.Writer.Owner != m.owner {
+		return tracer.Trace(hexa.ErrLockAlreadyAcquired)
+	}
+	return nil
+}
+
+func (m *rwMutex) Unlock(c context.Context) error {
+	_, err := m.coll.UpdateOne(c, bson.M{"_id": m.key, "writer.owner": m.owner}, bson.M{"$set": bson.M{"writer": nil}})
+	return tracer.Trace(err)
+}
+
+func (m *rwMutex) RLock(c context.Context) error {
+	return tracer.Trace(lockwait.Wait(c, m.wait, m.TryRLock, nil))
+}
+
+// TryRLock adds the owner to the readers if the lock is writable.
+func (m *rwMutex) TryRLock(c context.Context) error {
+	owner := literal(m.owner)
+	doc, err := m.tryLock(c, bson.M{"$set": bson.M{"readers": bson.M{"$cond": bson.A{
+		writable(owner),
+		withHolder("$readers", owner, time.Now().Add(m.ttl)),
+		"$readers",
+	}}}})
+	if err != nil {
+		return tracer.Trace(err)
+	}
+
+	if (doc.Writer != nil && doc.Writer.Owner != m.owner) || !hasHolder(doc.Readers, m.owner) {
+		return tracer.Trace(hexa.ErrLockAlreadyAcquired)
+	}
+	return nil
+}
+
+func (m *rwMutex) RUnlock(c context.Context) error {
+	_, err := m.coll.UpdateOne(c, bson.M{"_id": m.key}, bson.M{"$pull": bson.M{"readers": bson.M{"owner": m.owner}}})
+	return tracer.Trace(err)
+}
+
+var _ hexa.RWMutexManager = &dlm{}
+var _ hexa.RWMutex = &rwMutex{}
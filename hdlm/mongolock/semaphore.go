@@ -0,0 +1,139 @@
+package mongolock
+
+import (
+	"context"
+	"time"
+
+	"github.com/kamva/hexa"
+	"github.com/kamva/hexa/hdlm/internal/lockwait"
+	"github.com/kamva/tracer"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// holder is an owner of a semaphore permit or a read/write lock.
+type holder struct {
+	Owner  string    `bson:"owner"`
+	Expiry time.Time `bson:"expiry"`
+}
+
+func (m *dlm) NewSemaphore(o hexa.SemaphoreOptions) hexa.Semaphore {
+	o.Owner = m.ownerOf(o.Owner)
+	if o.Permits < 1 {
+		o.Permits = 1
+	}
+
+	return &semaphore{
+		coll:    m.semaphoreColl,
+		ttl:     o.TTL,
+		wait:    m.wait,
+		permits: o.Permits,
+		key:     o.Key,
+		owner:   o.Owner,
+	}
+}
+
+// semaphore implements hexa Semaphore using a document that keeps
+// holders of its permits and their last expiry:
+// {_id: "key", holders: [{owner: "me", expiry: date}], expiry: date}
+type semaphore struct {
+	coll    *mongo.Collection
+	ttl     time.Duration
+	wait    lockwait.Options
+	permits int
+	key     string
+	owner   string
+}
+
+func (s *semaphore) Acquire(c context.Context) error {
+	return tracer.Trace(lockwait.Wait(c, s.wait, s.TryAcquire, nil))
+}
+
+// TryAcquire removes the expired holders, then it adds the owner to the
+// holders if it's a holder already or there is a free permit.
+func (s *semaphore) TryAcquire(c context.Context) error {
+	now := time.Now()
+	owner := literal(s.owner)
+	update := bson.A{
+		bson.M{"$set": bson.M{"holders": unexpired(bson.M{"$ifNull": bson.A{"$holders", bson.A{}}}, now)}},
+		bson.M{"$set": bson.M{"holders": bson.M{"$cond": bson.A{
+			bson.M{"$or": bson.A{
+				bson.M{"$in": bson.A{owner, "$holders.owner"}},
+				bson.M{"$lt": bson.A{bson.M{"$size": "$holders"}, s.permits}},
+			}},
+			withHolder("$holders", owner, now.Add(s.ttl)),
+			"$holders",
+		}}}},
+		expiryStage(bson.M{"$max": "$holders.expiry"}, now),
+	}
+
+	var doc struct {
+		Holders []holder `bson:"holders"`
+	}
+	if err := findOneAndUpsert(c, s.coll, s.key, update, &doc); err != nil {
+		return tracer.Trace(err)
+	}
+	if !hasHolder(doc.Holders, s.owner) {
+		return tracer.Trace(hexa.ErrLockAlreadyAcquired)
+	}
+	return nil
+}
+
+func (s *semaphore) Release(c context.Context) error {
+	_, err := s.coll.UpdateOne(c, bson.M{"_id": s.key}, bson.M{"$pull": bson.M{"holders": bson.M{"owner": s.owner}}})
+	return tracer.Trace(err)
+}
+
+// unexpired returns an expression that filters the unexpired holders.
+func unexpired(holders any, now time.Time) bson.M {
+	return bson.M{"$filter": bson.M{
+		"input": holders,
+		"as":    "h",
+		"cond":  bson.M{"$gt": bson.A{"$$h.expiry", now}},
+	}}
+}
+
+// expiryStage returns a stage that sets expiry of the document to the
+// last expiry of its holders, or now if it has no holder, so the TTL
+// index removes the document once all of its holders are expired.
+func expiryStage(last bson.M, now time.Time) bson.M {
+	return bson.M{"$set": bson.M{"expiry": bson.M{"$ifNull": bson.A{last, now}}}}
+}
+
+// withHolder returns an expression that replaces the owner in holders
+// with a new holder.
+func withHolder(holders string, owner bson.M, expiry time.Time) bson.M {
+	return bson.M{"$concatArrays": bson.A{
+		withoutHolder(holders, owner),
+		bson.A{bson.M{"owner": owner, "expiry": expiry}},
+	}}
+}
+
+// withoutHolder returns an expression that removes the owner from holders.
+func withoutHolder(holders string, owner bson.M) bson.M {
+	return bson.M{"$filter": bson.M{
+		"input": holders,
+		"as":    "h",
+		"cond":  bson.M{"$ne": bson.A{"$$h.owner", owner}},
+	}}
+}
+
+func hasHolder(l []holder, owner string) bool {
+	for _, h := range l {
+		if h.Owner == owner {
+			return true
+		}
+	}
+	return false
+}
+
+// findOneAndUpsert updates (or inserts) the key's document using the
+// update pipeline and decodes the updated document to v.
+func findOneAndUpsert(c context.Context, coll *mongo.Collection, key string, update bson.A, v any) error {
+	o := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	return tracer.Trace(coll.FindOneAndUpdate(c, bson.M{"_id": key}, update, o).Decode(v))
+}
+
+var _ hexa.SemaphoreManager = &dlm{}
+var _ hexa.Semaphore = &semaphore{}
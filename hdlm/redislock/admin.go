@@ -0,0 +1,106 @@
+package redislock
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kamva/hexa"
+	"github.com/kamva/hexa/hlog"
+	"github.com/kamva/tracer"
+	"github.com/redis/go-redis/v9"
+)
+
+// luaForceUnlock deletes the lock and returns its value. It ignores keys
+// which are not strings. KEYS[1] is the lock key.
+var luaForceUnlock = redis.NewScript(`
+if redis.call("type", KEYS[1]).ok ~= "string" then return false end
+local v = redis.call("get", KEYS[1])
+redis.call("del", KEYS[1])
+return v
+`)
+
+// ErrOutsideLockPrefix is returned by the lock admin if a key is outside
+// the DlmOptions.LockPrefix.
+var ErrOutsideLockPrefix = errors.New("the key is outside the lock prefix")
+
+// globEscaper escapes special characters of redis glob patterns.
+var globEscaper = strings.NewReplacer(`\`, `\\`, `*`, `\*`, `?`, `\?`, `[`, `\[`, `]`, `\]`)
+
+// Locks returns locks whose key begins with the prefix. It just returns
+// keys with the DlmOptions.LockPrefix, e.g., an empty prefix returns all
+// of them. Please note lock values are their owners, except locks without
+// owner, whose values are random.
+func (m *dlm) Locks(c context.Context, prefix string) ([]hexa.LockInfo, error) {
+	if m.lockPrefix == "" {
+		return nil, tracer.Trace(ErrOutsideLockPrefix)
+	}
+	switch {
+	case strings.HasPrefix(prefix, m.lockPrefix):
+	case strings.HasPrefix(m.lockPrefix, prefix):
+		prefix = m.lockPrefix
+	default:
+		return []hexa.LockInfo{}, nil
+	}
+
+	var keys []string
+	iter := m.redis.ScanType(c, 0, globEscaper.Replace(prefix)+"*", 100, "string").Iterator()
+	for iter.Next(c) {
+		keys = append(keys, iter.Val())
+	}
+	if err := iter.Err(); err != nil {
+		return nil, tracer.Trace(err)
+	}
+	sort.Strings(keys)
+
+	pipe := m.redis.Pipeline()
+	owners := make([]*redis.StringCmd, len(keys))
+	ttls := make([]*redis.DurationCmd, len(keys))
+	for i, key := range keys {
+		owners[i] = pipe.Get(c, key)
+		ttls[i] = pipe.PTTL(c, key)
+	}
+	if _, err := pipe.Exec(c); err != nil && !errors.Is(err, redis.Nil) {
+		return nil, tracer.Trace(err)
+	}
+
+	now := time.Now()
+	locks := make([]hexa.LockInfo, 0, len(keys))
+	for i, key := range keys {
+		ttl := ttls[i].Val()
+		if owners[i].Err() != nil || ttl <= 0 { // it's expired or it has no ttl.
+			continue
+		}
+		locks = append(locks, hexa.LockInfo{Key: key, Owner: owners[i].Val(), Expiry: now.Add(ttl)})
+	}
+	return locks, nil
+}
+
+// ForceUnlock releases the lock. It refuses keys outside the
+// DlmOptions.LockPrefix.
+func (m *dlm) ForceUnlock(c context.Context, key string) error {
+	if m.lockPrefix == "" || !strings.HasPrefix(key, m.lockPrefix) {
+		return tracer.Trace(ErrOutsideLockPrefix)
+	}
+
+	owner, err := luaForceUnlock.Run(c, m.redis, []string{key}).Text()
+	if errors.Is(err, redis.Nil) {
+		return nil
+	}
+	if err != nil {
+		return tracer.Trace(err)
+	}
+
+	hlog.Warn("force released the lock", hlog.String("key", key), hlog.String("owner", owner))
+	m.waiter.released(c, key+ReleasedChannelSuffix)
+	return nil
+}
+
+// ClearExpired returns zero, redis removes expired locks by itself.
+func (m *dlm) ClearExpired(context.Context) (int64, error) {
+	return 0, nil
+}
+
+var _ hexa.LockAdmin = &dlm{}
@@ -7,25 +7,62 @@ import (
 
 	"github.com/bsm/redislock"
 	"github.com/kamva/hexa"
+	"github.com/kamva/hexa/hdlm/internal/lockwait"
 	"github.com/kamva/hexa/hlog"
 	"github.com/kamva/tracer"
 	"github.com/redis/go-redis/v9"
 )
 
+// luaFencing returns the fencing token of the lock. It issues a new token
+// if the lock has a new holder. KEYS[1] is the lock key, KEYS[2] is the
+// fencing key and ARGV[1] is the lock's value. It returns zero if the
+// lock is not held by the value.
+var luaFencing = redis.NewScript(`
+if redis.call("get", KEYS[1]) ~= ARGV[1] then return 0 end
+if redis.call("hget", KEYS[2], "holder") == ARGV[1] then return tonumber(redis.call("hget", KEYS[2], "token")) end
+redis.call("hset", KEYS[2], "holder", ARGV[1])
+return redis.call("hincrby", KEYS[2], "token", 1)
+`)
+
+// FencingKeySuffix is the suffix of the key that keeps fencing tokens of a
+// lock. That key never expires, so tokens always increase.
+const FencingKeySuffix = ":fencing"
+
 type DlmOptions struct {
 	Client          *redis.Client
 	WaitingInterval time.Duration
-	DefaultTTL      time.Duration
-	DefaultOwner    string
+	// MaxWaitingInterval enables exponential backoff: the waiting interval
+	// doubles on each try up to this value. zero means a fixed interval.
+	MaxWaitingInterval time.Duration
+	// WaitingJitter randomizes each waiting interval by up to this
+	// fraction of it, e.g., 0.2 means ±20%.
+	WaitingJitter float64
+	// MaxWait limits the time that Lock waits for a lock, then it returns
+	// the hexa.ErrLockAlreadyAcquired error. zero means no limit.
+	MaxWait time.Duration
+	// NotifyRelease publishes lock releases, so waiters try again as soon
+	// as a lock is released instead of sleeping for the waiting interval.
+	// Enable it on all instances that share locks.
+	NotifyRelease bool
+	DefaultTTL    time.Duration
+	DefaultOwner  string
+	// LockPrefix is the common prefix of your lock keys, e.g., "locks:".
+	// Locks are plain string keys in redis, so the lock admin just lists
+	// and force-releases keys with this prefix. It refuses all keys if
+	// the prefix is empty.
+	LockPrefix string
 }
 
 // dlm implements the Hexa DLM.
 type dlm struct {
 	hexa.Health
-	client   *redislock.Client
-	owner    string
-	ttl      time.Duration
-	interval time.Duration
+	redis  *redis.Client
+	client *redislock.Client
+	owner  string
+	ttl    time.Duration
+	waiter *waiter
+	// lockPrefix is the prefix of keys that the lock admin can access.
+	lockPrefix string
 }
 
 func NewDlm(o DlmOptions) (hexa.DLM, error) {
@@ -34,10 +71,23 @@ func NewDlm(o DlmOptions) (hexa.DLM, error) {
 			return o.Client.Ping(ctx).Err()
 		}, nil),
 
-		client:   redislock.New(o.Client),
-		ttl:      o.DefaultTTL,
-		owner:    o.DefaultOwner,
-		interval: o.WaitingInterval,
+		redis:      o.Client,
+		client:     redislock.New(o.Client),
+		ttl:        o.DefaultTTL,
+		owner:      o.DefaultOwner,
+		lockPrefix: o.LockPrefix,
+		waiter: &waiter{
+			redis: o.Client,
+			o: lockwait.Options{
+				Interval:    o.WaitingInterval,
+				MaxInterval: o.MaxWaitingInterval,
+				Jitter:      o.WaitingJitter,
+				MaxWait:     o.MaxWait,
+			},
+		},
+	}
+	if o.NotifyRelease {
+		dlm.waiter.notifier = newNotifier(o.Client)
 	}
 
 	return dlm, nil
@@ -60,27 +110,35 @@ func (m *dlm) NewMutexWithOptions(o hexa.MutexOptions) hexa.Mutex {
 		o.Owner = m.owner
 	}
 
-	return &mutex{
-		client:   m.client,
-		ttl:      o.TTL,
-		interval: m.interval,
+	var mu hexa.Mutex = &mutex{
+		redis:  m.redis,
+		client: m.client,
+		ttl:    o.TTL,
+		waiter: m.waiter,
 
 		ID:    o.Key,
 		Owner: o.Owner,
 	}
+	if o.KeepAlive {
+		return hexa.NewKeepAliveMutex(mu, o.TTL)
+	}
+	return mu
 }
 
 type mutex struct {
+	redis  *redis.Client
 	client *redislock.Client
 	// lock holds the currently acquired redis lock. It is set on a
 	// successful (Try)Lock and cleared on Unlock, and carries the token
 	// required to release or refresh the lock.
 	lock *redislock.Lock
 	ttl  time.Duration
+	// token is the fencing token of the current lock.
+	token int64
 
-	// interval is waiting time before try to lock again if
-	// lock already held by another mutex.
-	interval time.Duration
+	// waiter waits before try to lock again if lock
+	// already held by another mutex.
+	waiter *waiter
 
 	ID    string `json:"key" bson:"_id"`
 	Owner string `json:"owner" bson:"owner"`
@@ -89,19 +147,7 @@ type mutex struct {
 }
 
 func (m *mutex) Lock(c context.Context) error {
-	for {
-		err := m.TryLock(c)
-
-		if !errors.Is(err, hexa.ErrLockAlreadyAcquired) {
-			return tracer.Trace(err)
-		}
-
-		select {
-		case <-c.Done():
-			return c.Err()
-		case <-time.After(m.interval):
-		}
-	}
+	return tracer.Trace(m.waiter.wait(c, m.ID+ReleasedChannelSuffix, m.TryLock))
 }
 
 func (m *mutex) TryLock(c context.Context) error {
@@ -122,6 +168,7 @@ func (m *mutex) TryLock(c context.Context) error {
 			return tracer.Trace(err)
 		}
 		m.lock = nil // We lost the lock; fall through and try to obtain it again.
+		m.token = 0
 	}
 
 	lock, err := m.client.Obtain(c, m.ID, m.ttl, opts)
@@ -132,11 +179,28 @@ func (m *mutex) TryLock(c context.Context) error {
 		return tracer.Trace(err)
 	}
 
+	// The lock's value is its token, because we don't set metadata.
+	token, err := luaFencing.Run(c, m.redis, []string{m.ID, m.ID + FencingKeySuffix}, lock.Token()).Int64()
+	if err == nil && token == 0 { // the lock is expired already.
+		err = hexa.ErrLockAlreadyAcquired
+	}
+	if err != nil {
+		// Release the lock that we couldn't fence, we don't keep it, so
+		// Unlock can't release it.
+		_ = lock.Release(c)
+		return tracer.Trace(err)
+	}
+
 	m.lock = lock
+	m.token = token
 	m.Expiry = time.Now().Add(m.ttl)
 	return nil
 }
 
+func (m *mutex) Token() int64 {
+	return m.token
+}
+
 func (m *mutex) Unlock(c context.Context) error {
 	if m.lock == nil {
 		return nil
@@ -153,8 +217,12 @@ func (m *mutex) Unlock(c context.Context) error {
 	// Released, or already gone (ErrLockNotHeld, a no-op per the Mutex
 	// contract): drop the handle.
 	m.lock = nil
+	m.token = 0
+	if err == nil {
+		m.waiter.released(c, m.ID+ReleasedChannelSuffix)
+	}
 	return nil
 }
 
 var _ hexa.DLM = &dlm{}
-var _ hexa.Mutex = &mutex{}
+var _ hexa.FencedMutex = &mutex{}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/kamva/hexa"
+	"github.com/kamva/hexa/hdlm/dlmtest"
 	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -57,6 +58,27 @@ func TestNewMutexWithOptions_OwnerDefaulting(t *testing.T) {
 	assert.Equal(t, "other", withExplicit.Owner)
 }
 
+func TestNewSemaphoreAndRWMutex_Defaults(t *testing.T) {
+	d := newDlm(t, "machine-1", time.Minute)
+
+	s := d.(hexa.SemaphoreManager).NewSemaphore(hexa.SemaphoreOptions{Key: "key", TTL: time.Second}).(*semaphore)
+	assert.Equal(t, "key"+SemaphoreKeySuffix, s.key)
+	assert.Equal(t, "machine-1", s.owner)
+	assert.Equal(t, 1, s.permits)
+
+	rw := d.(hexa.RWMutexManager).NewRWMutex(hexa.MutexOptions{Key: "key", Owner: "other"}).(*rwMutex)
+	assert.Equal(t, "key"+WriterKeySuffix, rw.writerKey)
+	assert.Equal(t, "key"+ReadersKeySuffix, rw.readersKey)
+	assert.Equal(t, "other", rw.owner)
+
+	// Without any owner, each semaphore gets its own owner.
+	noOwner := newDlm(t, "", time.Minute).(hexa.SemaphoreManager)
+	s1 := noOwner.NewSemaphore(hexa.SemaphoreOptions{Key: "key"}).(*semaphore)
+	s2 := noOwner.NewSemaphore(hexa.SemaphoreOptions{Key: "key"}).(*semaphore)
+	assert.NotEmpty(t, s1.owner)
+	assert.NotEqual(t, s1.owner, s2.owner)
+}
+
 // TestNewDlm_InitializesHealth is a regression test: the embedded Health used
 // to be nil, so any health call on the DLM panicked with a nil-interface
 // dispatch. It must now be a usable, non-nil Health.
@@ -108,3 +130,101 @@ func TestMutex_LockUnlock_Integration(t *testing.T) {
 	require.NoError(t, m2.TryLock(ctx))
 	require.NoError(t, m2.Unlock(ctx))
 }
+
+func TestConformance_Integration(t *testing.T) {
+	client := redis.NewClient(&redis.Options{Addr: redisAddr(t)})
+	defer client.Close()
+
+	newDlm := func(t *testing.T) hexa.DLM {
+		d, err := NewDlm(DlmOptions{
+			Client:          client,
+			DefaultTTL:      time.Minute,
+			WaitingInterval: 20 * time.Millisecond,
+			LockPrefix:      "dlmtest-",
+		})
+		require.NoError(t, err)
+		return d
+	}
+
+	dlmtest.Run(t, newDlm)
+	dlmtest.RunSemaphore(t, func(t *testing.T) hexa.SemaphoreManager { return newDlm(t).(hexa.SemaphoreManager) })
+	dlmtest.RunRWMutex(t, func(t *testing.T) hexa.RWMutexManager { return newDlm(t).(hexa.RWMutexManager) })
+	dlmtest.RunLockAdmin(t, func(t *testing.T) dlmtest.Admin { return newDlm(t).(dlmtest.Admin) })
+}
+
+func TestConformance_NotifyRelease_Integration(t *testing.T) {
+	client := redis.NewClient(&redis.Options{Addr: redisAddr(t)})
+	defer client.Close()
+
+	newDlm := func(t *testing.T) hexa.DLM {
+		d, err := NewDlm(DlmOptions{
+			Client:             client,
+			DefaultTTL:         time.Minute,
+			WaitingInterval:    20 * time.Millisecond,
+			MaxWaitingInterval: 100 * time.Millisecond,
+			WaitingJitter:      0.2,
+			NotifyRelease:      true,
+			LockPrefix:         "dlmtest-",
+		})
+		require.NoError(t, err)
+		return d
+	}
+
+	dlmtest.Run(t, newDlm)
+	dlmtest.RunSemaphore(t, func(t *testing.T) hexa.SemaphoreManager { return newDlm(t).(hexa.SemaphoreManager) })
+	dlmtest.RunRWMutex(t, func(t *testing.T) hexa.RWMutexManager { return newDlm(t).(hexa.RWMutexManager) })
+	dlmtest.RunLockAdmin(t, func(t *testing.T) dlmtest.Admin { return newDlm(t).(dlmtest.Admin) })
+}
+
+func TestNotifyRelease_Integration(t *testing.T) {
+	client := redis.NewClient(&redis.Options{Addr: redisAddr(t)})
+	defer client.Close()
+
+	d, err := NewDlm(DlmOptions{
+		Client:          client,
+		DefaultTTL:      time.Minute,
+		WaitingInterval: time.Minute,
+		NotifyRelease:   true,
+	})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	key := fmt.Sprintf("hexa-redislock-itest-%s-%d", t.Name(), time.Now().UnixNano())
+	m1 := d.NewMutexWithOptions(hexa.MutexOptions{Key: key, Owner: "m1", TTL: time.Minute})
+	m2 := d.NewMutexWithOptions(hexa.MutexOptions{Key: key, Owner: "m2", TTL: time.Minute})
+	require.NoError(t, m1.Lock(ctx))
+
+	locked := make(chan error, 1)
+	go func() { locked <- m2.Lock(ctx) }()
+	time.Sleep(100 * time.Millisecond) // let it subscribe.
+	require.NoError(t, m1.Unlock(ctx))
+
+	// It doesn't wait for the waiting interval.
+	select {
+	case err := <-locked:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the waiter didn't get the release notification")
+	}
+	require.NoError(t, m2.Unlock(ctx))
+}
+
+func TestLockAdmin_RefusesKeysOutsideLockPrefix(t *testing.T) {
+	ctx := context.Background()
+	// The admin refuses the keys before it sends any command to redis.
+	client := redis.NewClient(&redis.Options{Addr: "127.0.0.1:0"})
+	defer client.Close()
+
+	d, err := NewDlm(DlmOptions{Client: client})
+	require.NoError(t, err)
+	_, err = d.(hexa.LockAdmin).Locks(ctx, "")
+	assert.ErrorIs(t, err, ErrOutsideLockPrefix)
+	assert.ErrorIs(t, d.(hexa.LockAdmin).ForceUnlock(ctx, "session:1"), ErrOutsideLockPrefix)
+
+	d, err = NewDlm(DlmOptions{Client: client, LockPrefix: "locks:"})
+	require.NoError(t, err)
+	assert.ErrorIs(t, d.(hexa.LockAdmin).ForceUnlock(ctx, "session:1"), ErrOutsideLockPrefix)
+	locks, err := d.(hexa.LockAdmin).Locks(ctx, "session:")
+	require.NoError(t, err)
+	assert.Empty(t, locks)
+}
@@ -0,0 +1,139 @@
+package redislock
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/kamva/hexa"
+	"github.com/kamva/hexa/hdlm/internal/lockwait"
+	"github.com/kamva/hexa/hlog"
+	"github.com/kamva/tracer"
+	"github.com/redis/go-redis/v9"
+)
+
+// ReleasedChannelSuffix is the suffix of the pub/sub channel of a lock which
+// notifies its waiters when the lock is released.
+const ReleasedChannelSuffix = ":released"
+
+// waiter waits for locks and notifies waiters of released locks.
+type waiter struct {
+	redis *redis.Client
+	o     lockwait.Options
+	// notifier is nil if release notifications are disabled.
+	notifier *notifier
+}
+
+// wait calls tryLock until it acquires the lock. If release notifications
+// are enabled, it subscribes to the lock's channel and tries again as soon
+// as the lock is released. Notifications just speed up waiting, the
+// backoff still applies when a notification is missed.
+func (w *waiter) wait(c context.Context, channel string, tryLock func(c context.Context) error) error {
+	if w.notifier == nil {
+		return lockwait.Wait(c, w.o, tryLock, nil)
+	}
+
+	// Most locks are free, so don't subscribe before the first try.
+	if err := tryLock(c); !errors.Is(err, hexa.ErrLockAlreadyAcquired) {
+		return err
+	}
+
+	released, unsubscribe, err := w.notifier.subscribe(c, channel)
+	if err != nil {
+		return tracer.Trace(err)
+	}
+	defer unsubscribe()
+	return lockwait.Wait(c, w.o, tryLock, released)
+}
+
+// released notifies waiters of the lock that it's released.
+func (w *waiter) released(c context.Context, channel string) {
+	if w.notifier == nil {
+		return
+	}
+	// The lock is released already, so don't fail, waiters try again
+	// after their backoff anyway.
+	if err := w.redis.Publish(c, channel, "").Err(); err != nil {
+		hlog.Warn("failed to publish the lock release", hlog.String("channel", channel), hlog.Err(err))
+	}
+}
+
+// notifier dispatches release notifications to waiters using a single
+// pub/sub connection, which is open just while there are waiters.
+type notifier struct {
+	redis *redis.Client
+
+	mu      sync.Mutex
+	pubsub  *redis.PubSub
+	waiters map[string]map[chan struct{}]struct{}
+}
+
+func newNotifier(client *redis.Client) *notifier {
+	return &notifier{
+		redis:   client,
+		waiters: make(map[string]map[chan struct{}]struct{}),
+	}
+}
+
+// subscribe returns a channel that receives when the lock is released.
+// Call unsubscribe when you're done.
+func (n *notifier) subscribe(c context.Context, channel string) (released <-chan struct{}, unsubscribe func(), err error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.pubsub == nil {
+		n.pubsub = n.redis.Subscribe(c)
+		go n.dispatch(n.pubsub.Channel())
+	}
+	if len(n.waiters[channel]) == 0 {
+		if err := n.pubsub.Subscribe(c, channel); err != nil {
+			n.closeIfIdle()
+			return nil, nil, tracer.Trace(err)
+		}
+		n.waiters[channel] = make(map[chan struct{}]struct{})
+	}
+
+	ch := make(chan struct{}, 1)
+	n.waiters[channel][ch] = struct{}{}
+	return ch, func() { n.unsubscribe(channel, ch) }, nil
+}
+
+func (n *notifier) unsubscribe(channel string, ch chan struct{}) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	delete(n.waiters[channel], ch)
+	if len(n.waiters[channel]) != 0 {
+		return
+	}
+	delete(n.waiters, channel)
+	if err := n.pubsub.Unsubscribe(context.Background(), channel); err != nil {
+		hlog.Warn("failed to unsubscribe from the lock release channel", hlog.String("channel", channel), hlog.Err(err))
+	}
+	n.closeIfIdle()
+}
+
+// closeIfIdle closes the pub/sub connection if there is no waiter.
+// Call it while you hold the lock.
+func (n *notifier) closeIfIdle() {
+	if len(n.waiters) != 0 {
+		return
+	}
+	if err := n.pubsub.Close(); err != nil {
+		hlog.Warn("failed to close the lock release subscription", hlog.Err(err))
+	}
+	n.pubsub = nil
+}
+
+func (n *notifier) dispatch(messages <-chan *redis.Message) {
+	for msg := range messages {
+		n.mu.Lock()
+		for ch := range n.waiters[msg.Channel] {
+			select {
+			case ch <- struct{}{}:
+			default: // it has a pending notification already.
+			}
+		}
+		n.mu.Unlock()
+	}
+}
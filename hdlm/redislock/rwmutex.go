@@ -0,0 +1,120 @@
+package redislock
+
+import (
+	"context"
+	"time"
+
+	"github.com/kamva/hexa"
+	"github.com/kamva/tracer"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// WriterKeySuffix is the suffix of the key of a read/write lock's
+	// writer. Its value is the writer's owner.
+	WriterKeySuffix = ":rw:writer"
+	// ReadersKeySuffix is the suffix of the key of a read/write lock's
+	// readers. Its value is a sorted set of owners scored by their expiry.
+	ReadersKeySuffix = ":rw:readers"
+)
+
+// KEYS[1] is the writer key, KEYS[2] is the readers key, ARGV is owner
+// and ttl (ms).
+var (
+	luaRLock = redis.NewScript(luaNow + `
+local w = redis.call("get", KEYS[1])
+if w and w ~= ARGV[1] then return 0 end
+local ttl = tonumber(ARGV[2])
+redis.call("zremrangebyscore", KEYS[2], "-inf", now)
+redis.call("zadd", KEYS[2], now + ttl, ARGV[1])
+if redis.call("pttl", KEYS[2]) < ttl then redis.call("pexpire", KEYS[2], ttl) end
+return 1
+`)
+
+	luaWLock = redis.NewScript(luaNow + `
+local w = redis.call("get", KEYS[1])
+if w and w ~= ARGV[1] then return 0 end
+redis.call("zremrangebyscore", KEYS[2], "-inf", now)
+local n = redis.call("zcard", KEYS[2])
+if n > 1 or (n == 1 and not redis.call("zscore", KEYS[2], ARGV[1])) then return 0 end
+redis.call("set", KEYS[1], ARGV[1], "PX", ARGV[2])
+return 1
+`)
+
+	// KEYS[1] is the writer key, ARGV[1] is the owner.
+	luaWUnlock = redis.NewScript(`
+if redis.call("get", KEYS[1]) == ARGV[1] then return redis.call("del", KEYS[1]) end
+return 0
+`)
+)
+
+func (m *dlm) NewRWMutex(o hexa.MutexOptions) hexa.RWMutex {
+	return &rwMutex{
+		redis:      m.redis,
+		ttl:        o.TTL,
+		waiter:     m.waiter,
+		channel:    o.Key + ":rw" + ReleasedChannelSuffix,
+		writerKey:  o.Key + WriterKeySuffix,
+		readersKey: o.Key + ReadersKeySuffix,
+		owner:      m.ownerOf(o.Owner),
+	}
+}
+
+// rwMutex implements hexa RWMutex using a redis key for the
+// writer and a sorted set for readers.
+type rwMutex struct {
+	redis      *redis.Client
+	ttl        time.Duration
+	waiter     *waiter
+	channel    string
+	writerKey  string
+	readersKey string
+	owner      string
+}
+
+func (m *rwMutex) tryLock(c context.Context, script *redis.Script) error {
+	ok, err := script.Run(c, m.redis, []string{m.writerKey, m.readersKey},
+		m.owner, m.ttl.Milliseconds()).Bool()
+	if err != nil {
+		return tracer.Trace(err)
+	}
+	if !ok {
+		return tracer.Trace(hexa.ErrLockAlreadyAcquired)
+	}
+	return nil
+}
+
+func (m *rwMutex) Lock(c context.Context) error {
+	return tracer.Trace(m.waiter.wait(c, m.channel, m.TryLock))
+}
+
+func (m *rwMutex) TryLock(c context.Context) error {
+	return m.tryLock(c, luaWLock)
+}
+
+func (m *rwMutex) Unlock(c context.Context) error {
+	if err := luaWUnlock.Run(c, m.redis, []string{m.writerKey}, m.owner).Err(); err != nil {
+		return tracer.Trace(err)
+	}
+	m.waiter.released(c, m.channel)
+	return nil
+}
+
+func (m *rwMutex) RLock(c context.Context) error {
+	return tracer.Trace(m.waiter.wait(c, m.channel, m.TryRLock))
+}
+
+func (m *rwMutex) TryRLock(c context.Context) error {
+	return m.tryLock(c, luaRLock)
+}
+
+func (m *rwMutex) RUnlock(c context.Context) error {
+	if err := m.redis.ZRem(c, m.readersKey, m.owner).Err(); err != nil {
+		return tracer.Trace(err)
+	}
+	m.waiter.released(c, m.channel)
+	return nil
+}
+
+var _ hexa.RWMutexManager = &dlm{}
+var _ hexa.RWMutex = &rwMutex{}
@@ -0,0 +1,104 @@
+package redislock
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"time"
+
+	"github.com/kamva/hexa"
+	"github.com/kamva/tracer"
+	"github.com/redis/go-redis/v9"
+)
+
+// SemaphoreKeySuffix is the suffix of the key of a semaphore. Its value is
+// a sorted set of owners scored by their permit's expiry.
+const SemaphoreKeySuffix = ":semaphore"
+
+// luaNow sets now to the redis server's time (ms), so the expiry of
+// permits doesn't depend on clocks of the clients. Redis < 7 needs the
+// replication of effects to write after reading the time.
+const luaNow = `
+redis.replicate_commands()
+local t = redis.call("time")
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+`
+
+// luaAcquire acquires or refreshes a permit. KEYS[1] is the semaphore
+// key, ARGV is owner, permits and ttl (ms).
+var luaAcquire = redis.NewScript(luaNow + `
+local ttl = tonumber(ARGV[3])
+redis.call("zremrangebyscore", KEYS[1], "-inf", now)
+if not redis.call("zscore", KEYS[1], ARGV[1]) and redis.call("zcard", KEYS[1]) >= tonumber(ARGV[2]) then return 0 end
+redis.call("zadd", KEYS[1], now + ttl, ARGV[1])
+if redis.call("pttl", KEYS[1]) < ttl then redis.call("pexpire", KEYS[1], ttl) end
+return 1
+`)
+
+func (m *dlm) NewSemaphore(o hexa.SemaphoreOptions) hexa.Semaphore {
+	if o.Permits < 1 {
+		o.Permits = 1
+	}
+
+	return &semaphore{
+		redis:   m.redis,
+		ttl:     o.TTL,
+		waiter:  m.waiter,
+		permits: o.Permits,
+		key:     o.Key + SemaphoreKeySuffix,
+		owner:   m.ownerOf(o.Owner),
+	}
+}
+
+// ownerOf returns the owner of a new semaphore or read/write lock. If
+// owner is empty, it returns the default owner. If that's empty too, it
+// returns a random owner, just like mutexes without owner.
+func (m *dlm) ownerOf(owner string) string {
+	if owner == "" {
+		owner = m.owner
+	}
+	if owner != "" {
+		return owner
+	}
+
+	b := make([]byte, 16)
+	_, _ = rand.Read(b) // it never returns an error.
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// semaphore implements hexa Semaphore using a redis sorted set.
+type semaphore struct {
+	redis   *redis.Client
+	ttl     time.Duration
+	waiter  *waiter
+	permits int
+	key     string
+	owner   string
+}
+
+func (s *semaphore) Acquire(c context.Context) error {
+	return tracer.Trace(s.waiter.wait(c, s.key+ReleasedChannelSuffix, s.TryAcquire))
+}
+
+func (s *semaphore) TryAcquire(c context.Context) error {
+	ok, err := luaAcquire.Run(c, s.redis, []string{s.key},
+		s.owner, s.permits, s.ttl.Milliseconds()).Bool()
+	if err != nil {
+		return tracer.Trace(err)
+	}
+	if !ok {
+		return tracer.Trace(hexa.ErrLockAlreadyAcquired)
+	}
+	return nil
+}
+
+func (s *semaphore) Release(c context.Context) error {
+	if err := s.redis.ZRem(c, s.key, s.owner).Err(); err != nil {
+		return tracer.Trace(err)
+	}
+	s.waiter.released(c, s.key+ReleasedChannelSuffix)
+	return nil
+}
+
+var _ hexa.SemaphoreManager = &dlm{}
+var _ hexa.Semaphore = &semaphore{}
@@ -0,0 +1,61 @@
+package sqllock
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/kamva/hexa"
+	"github.com/kamva/hexa/hlog"
+	"github.com/kamva/tracer"
+)
+
+func (m *dlm) Locks(c context.Context, prefix string) ([]hexa.LockInfo, error) {
+	rows, err := m.db.QueryContext(c, m.q.locks, len(prefix), prefix, time.Now().UnixMilli())
+	if err != nil {
+		return nil, tracer.Trace(err)
+	}
+	defer rows.Close()
+
+	locks := make([]hexa.LockInfo, 0)
+	for rows.Next() {
+		var l hexa.LockInfo
+		var expiry int64
+		if err := rows.Scan(&l.Key, &l.Owner, &expiry); err != nil {
+			return nil, tracer.Trace(err)
+		}
+		l.Expiry = time.UnixMilli(expiry)
+		locks = append(locks, l)
+	}
+	return locks, tracer.Trace(rows.Err())
+}
+
+func (m *dlm) ForceUnlock(c context.Context, key string) error {
+	var owner string
+	var expiry int64
+	err := m.db.QueryRowContext(c, m.q.owner, key).Scan(&owner, &expiry)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return tracer.Trace(err)
+	}
+
+	if _, err := m.db.ExecContext(c, m.q.forceUnlock, key); err != nil {
+		return tracer.Trace(err)
+	}
+	hlog.Warn("force released the lock", hlog.String("key", key), hlog.String("owner", owner), hlog.Time("expiry", time.UnixMilli(expiry)))
+	return nil
+}
+
+func (m *dlm) ClearExpired(c context.Context) (int64, error) {
+	res, err := m.db.ExecContext(c, m.q.clearExpired, time.Now().UnixMilli())
+	if err != nil {
+		return 0, tracer.Trace(err)
+	}
+	n, err := res.RowsAffected()
+	return n, tracer.Trace(err)
+}
+
+var _ hexa.LockAdmin = &dlm{}
@@ -0,0 +1,241 @@
+package sqllock
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kamva/hexa"
+	"github.com/kamva/hexa/hdlm/internal/lockwait"
+	"github.com/kamva/hexa/hlog"
+	"github.com/kamva/tracer"
+)
+
+// TableName is default table name.
+const TableName = "locks"
+
+type DlmOptions struct {
+	DB *sql.DB
+	// Table is the locks table. NewDlm creates it if it doesn't exist.
+	// Please note it's a part of queries, so don't get it from users.
+	// Default value is TableName.
+	Table string
+	// ExpiryIndex is name of the index of the expiry column. Default
+	// value is the table name without its schema plus "_expiry", e.g.,
+	// "locks_expiry" for "app.locks".
+	ExpiryIndex string
+	// If a lock already held by another mutex, we need to interval
+	// to check it again.
+	WaitingInterval time.Duration
+	// MaxWaitingInterval enables exponential backoff: the waiting interval
+	// doubles on each try up to this value. zero means a fixed interval.
+	MaxWaitingInterval time.Duration
+	// WaitingJitter randomizes each waiting interval by up to this
+	// fraction of it, e.g., 0.2 means ±20%.
+	WaitingJitter float64
+	// MaxWait limits the time that Lock waits for a lock, then it returns
+	// the hexa.ErrLockAlreadyAcquired error. zero means no limit.
+	MaxWait time.Duration
+	// Default ttl value for a lock. e.g, 2s.
+	DefaultTTL time.Duration
+	// usually your machine name. e.g., k8s-pod-199831uf.
+	DefaultOwner string
+}
+
+// queries are the queries of a locks table.
+type queries struct {
+	lock         string
+	unlock       string
+	locks        string
+	forceUnlock  string
+	owner        string
+	clearExpired string
+}
+
+func newQueries(table string) queries {
+	return queries{
+		// lock inserts the lock, or updates it if it's our lock or it's
+		// expired, just like the mongolock filter.
+		lock: fmt.Sprintf(`INSERT INTO %[1]s (id, owner, expiry) VALUES ($1, $2, $3)
+ON CONFLICT (id) DO UPDATE SET owner = excluded.owner, expiry = excluded.expiry
+WHERE %[1]s.owner = excluded.owner OR %[1]s.expiry < $4`, table),
+		unlock:       fmt.Sprintf(`DELETE FROM %s WHERE id = $1 AND owner = $2`, table),
+		locks:        fmt.Sprintf(`SELECT id, owner, expiry FROM %s WHERE substr(id, 1, $1) = $2 AND expiry > $3 ORDER BY id`, table),
+		owner:        fmt.Sprintf(`SELECT owner, expiry FROM %s WHERE id = $1`, table),
+		forceUnlock:  fmt.Sprintf(`DELETE FROM %s WHERE id = $1`, table),
+		clearExpired: fmt.Sprintf(`DELETE FROM %s WHERE expiry < $1`, table),
+	}
+}
+
+// dlm implements the Hexa DLM.
+type dlm struct {
+	hexa.Health
+
+	db    *sql.DB
+	table string
+	index string
+	q     queries
+	// owner is default lock owner.
+	owner string
+	// ttl is default lock ttl.
+	ttl time.Duration
+	// wait is how to wait before try to lock again if lock
+	// already held by another mutex.
+	wait lockwait.Options
+}
+
+func NewDlm(o DlmOptions) (hexa.DLM, error) {
+	if o.Table == "" {
+		o.Table = TableName
+	}
+	if o.ExpiryIndex == "" {
+		o.ExpiryIndex = expiryIndex(o.Table)
+	}
+
+	dlm := &dlm{
+		Health: hexa.NewPingHealth(hlog.GlobalLogger(), "distributed_locks", o.DB.PingContext, nil),
+
+		db:    o.DB,
+		table: o.Table,
+		index: o.ExpiryIndex,
+		q:     newQueries(o.Table),
+		ttl:   o.DefaultTTL,
+		owner: o.DefaultOwner,
+		wait: lockwait.Options{
+			Interval:    o.WaitingInterval,
+			MaxInterval: o.MaxWaitingInterval,
+			Jitter:      o.WaitingJitter,
+			MaxWait:     o.MaxWait,
+		},
+	}
+
+	return dlm, tracer.Trace(dlm.createTableIfNotExist())
+}
+
+func (m *dlm) createTableIfNotExist() error {
+	// expiry is unix time in milliseconds, so it's portable between databases.
+	_, err := m.db.Exec(fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	id VARCHAR(255) PRIMARY KEY,
+	owner VARCHAR(255) NOT NULL,
+	expiry BIGINT NOT NULL
+)`, m.table))
+	if err != nil {
+		return tracer.Trace(err)
+	}
+
+	// Please note this index doesn't have any effect on the mutex behavior,
+	// its just for cleanup.
+	_, err = m.db.Exec(fmt.Sprintf(`CREATE INDEX IF NOT EXISTS %s ON %s (expiry)`, m.index, m.table))
+	return tracer.Trace(err)
+}
+
+// expiryIndex returns the default name of the expiry index of the table.
+// Indexes live in the schema of their table, so their name can't have a
+// schema, e.g., it returns "locks_expiry" for `app."locks"`.
+func expiryIndex(table string) string {
+	name := table[strings.LastIndex(table, ".")+1:]
+	return strings.Trim(name, "\"`[]") + "_expiry"
+}
+
+func (m *dlm) NewMutex(Key string) hexa.Mutex {
+	return m.NewMutexWithOptions(hexa.MutexOptions{
+		Key:   Key,
+		Owner: m.owner,
+		TTL:   m.ttl,
+	})
+}
+
+func (m *dlm) NewMutexWithTTL(Key string, ttl time.Duration) hexa.Mutex {
+	return m.NewMutexWithOptions(hexa.MutexOptions{Key: Key, TTL: ttl})
+}
+
+func (m *dlm) NewMutexWithOptions(o hexa.MutexOptions) hexa.Mutex {
+	o.Owner = m.ownerOf(o.Owner)
+
+	var mu hexa.Mutex = &mutex{
+		db:   m.db,
+		q:    &m.q,
+		ttl:  o.TTL,
+		wait: m.wait,
+
+		ID:    o.Key,
+		Owner: o.Owner,
+	}
+	if o.KeepAlive {
+		return hexa.NewKeepAliveMutex(mu, o.TTL)
+	}
+	return mu
+}
+
+// ownerOf returns the owner of a new mutex. If owner is empty, it returns
+// the default owner. If that's empty too, it returns a random owner, so
+// mutexes without owner don't share their locks.
+func (m *dlm) ownerOf(owner string) string {
+	if owner == "" {
+		owner = m.owner
+	}
+	if owner != "" {
+		return owner
+	}
+
+	b := make([]byte, 16)
+	_, _ = rand.Read(b) // it never returns an error.
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// mutex implements hexa Mutex distributed lock using a SQL table.
+// it uses an upsert that updates the lock just if it's our lock or
+// it's expired, otherwise it doesn't change any row, which means the
+// key is held by another mutex.
+type mutex struct {
+	db  *sql.DB
+	q   *queries
+	ttl time.Duration
+
+	// wait is how to wait before try to lock again if lock
+	// already held by another mutex.
+	wait lockwait.Options
+
+	ID    string `json:"key"`
+	Owner string `json:"owner"`
+	// Expiry begins when we lock the mutex.
+	Expiry time.Time `json:"expiry"`
+}
+
+// Lock try to lock and if lock is held by another mutex, it wait and
+// try it again.
+func (m *mutex) Lock(c context.Context) error {
+	return tracer.Trace(lockwait.Wait(c, m.wait, m.TryLock, nil))
+}
+
+func (m *mutex) TryLock(c context.Context) error {
+	now := time.Now()
+	expiry := now.Add(m.ttl)
+
+	res, err := m.db.ExecContext(c, m.q.lock, m.ID, m.Owner, expiry.UnixMilli(), now.UnixMilli())
+	if err != nil {
+		return tracer.Trace(err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return tracer.Trace(err)
+	}
+	if n == 0 {
+		return tracer.Trace(hexa.ErrLockAlreadyAcquired)
+	}
+
+	m.Expiry = expiry
+	return nil
+}
+
+func (m *mutex) Unlock(c context.Context) error {
+	_, err := m.db.ExecContext(c, m.q.unlock, m.ID, m.Owner)
+	return tracer.Trace(err)
+}
+
+var _ hexa.DLM = &dlm{}
+var _ hexa.Mutex = &mutex{}
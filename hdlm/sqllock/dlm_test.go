@@ -0,0 +1,22 @@
+package sqllock
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpiryIndex(t *testing.T) {
+	cases := []struct {
+		table string
+		index string
+	}{
+		{"locks", "locks_expiry"},
+		{"app.locks", "locks_expiry"},
+		{`"app"."locks"`, "locks_expiry"},
+		{"db.app.locks", "locks_expiry"},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.index, expiryIndex(c.table), c.table)
+	}
+}
@@ -0,0 +1,5 @@
+// Package sqllock implements hexa's distributed lock manager (hexa.DLM) on
+// top of database/sql. It needs a database which supports upserts with
+// the ON CONFLICT clause and $N placeholders, e.g., PostgreSQL 9.5+ and
+// SQLite 3.24+.
+package sqllock
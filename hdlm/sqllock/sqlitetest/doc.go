@@ -0,0 +1,6 @@
+// Package sqlitetest tests the sqllock driver against in-process SQLite.
+// It's a separate module, so the cgo SQLite driver isn't a dependency of
+// hexa. Run its tests in this directory:
+//
+//	go test ./...
+package sqlitetest
@@ -0,0 +1,84 @@
+package sqlitetest
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kamva/hexa"
+	"github.com/kamva/hexa/hdlm/dlmtest"
+	"github.com/kamva/hexa/hdlm/sqllock"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "locks.db"))
+	require.NoError(t, err)
+	// SQLite allows one writer at a time.
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = db.Close() })
+	return db
+}
+
+func newDlm(t *testing.T, db *sql.DB) hexa.DLM {
+	t.Helper()
+	d, err := sqllock.NewDlm(sqllock.DlmOptions{
+		DB:              db,
+		WaitingInterval: 20 * time.Millisecond,
+		DefaultTTL:      time.Minute,
+	})
+	require.NoError(t, err)
+	return d
+}
+
+func TestConformance(t *testing.T) {
+	dlmtest.Run(t, func(t *testing.T) hexa.DLM { return newDlm(t, newDB(t)) })
+	dlmtest.RunLockAdmin(t, func(t *testing.T) dlmtest.Admin { return newDlm(t, newDB(t)).(dlmtest.Admin) })
+}
+
+func TestNewDlm_CreatesTableOnce(t *testing.T) {
+	db := newDB(t)
+	ctx := context.Background()
+	require.NoError(t, newDlm(t, db).NewMutex("key").TryLock(ctx))
+
+	// The second DLM uses the existing table and its locks.
+	d := newDlm(t, db)
+	m := d.NewMutexWithOptions(hexa.MutexOptions{Key: "key", Owner: "other", TTL: time.Minute})
+	assert.ErrorIs(t, m.TryLock(ctx), hexa.ErrLockAlreadyAcquired)
+}
+
+func TestMutexDataInDB(t *testing.T) {
+	db := newDB(t)
+	ctx := context.Background()
+	d, err := sqllock.NewDlm(sqllock.DlmOptions{DB: db, Table: "app_locks", DefaultTTL: time.Minute, DefaultOwner: "lab"})
+	require.NoError(t, err)
+
+	m := d.NewMutex("abc")
+	before := time.Now().Add(time.Minute).UnixMilli()
+	require.NoError(t, m.TryLock(ctx))
+	after := time.Now().Add(time.Minute).UnixMilli()
+
+	var owner string
+	var expiry int64
+	require.NoError(t, db.QueryRow("SELECT owner, expiry FROM app_locks WHERE id = 'abc'").Scan(&owner, &expiry))
+	assert.Equal(t, "lab", owner)
+	assert.GreaterOrEqual(t, expiry, before)
+	assert.LessOrEqual(t, expiry, after)
+
+	require.NoError(t, m.Unlock(ctx))
+	assert.ErrorIs(t, db.QueryRow("SELECT owner FROM app_locks WHERE id = 'abc'").Scan(&owner), sql.ErrNoRows)
+}
+
+func TestMutex_WithoutOwner(t *testing.T) {
+	d, err := sqllock.NewDlm(sqllock.DlmOptions{DB: newDB(t), DefaultTTL: time.Minute})
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	require.NoError(t, d.NewMutex("key").TryLock(ctx))
+	assert.ErrorIs(t, d.NewMutex("key").TryLock(ctx), hexa.ErrLockAlreadyAcquired)
+}
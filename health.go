@@ -2,6 +2,8 @@ package hexa
 
 import (
 	"context"
+	"reflect"
+	"sync"
 )
 
 type ReadinessStatus string
@@ -18,6 +20,17 @@ const (
 	StatusDead  LivenessStatus = "DEAD"
 )
 
+type StartupStatus string
+
+const (
+	StatusStarted    StartupStatus = "STARTED"
+	StatusNotStarted StartupStatus = "NOT_STARTED"
+)
+
+// HealthErrorTag is the health status tag which checks can use
+// to report the reason of their failure.
+const HealthErrorTag = "error"
+
 type (
 	LivenessResult struct {
 		Id     string `json:"id"`
@@ -61,10 +74,41 @@ type HealthReporter interface {
 	HealthReport(ctx context.Context) HealthReport
 }
 
+// StartupReporter is implemented by health reporters which support
+// the startup probe.
+type StartupReporter interface {
+	// AddStartupChecks adds checks for the startup probe. A startup
+	// check reports the app as started once it's ready.
+	AddStartupChecks(l ...Health) HealthReporter
+	StartupStatus(ctx context.Context) StartupStatus
+}
+
+// HealthCheckRemover is implemented by health reporters which can
+// remove their checks.
+type HealthCheckRemover interface {
+	// RemoveChecks removes the checks from all probes.
+	RemoveChecks(l ...Health) HealthReporter
+}
+
+// HealthSubscriber is implemented by health reporters which emit
+// events when their checks change state.
+type HealthSubscriber interface {
+	// Subscribe returns a channel which receives an event whenever
+	// a check changes its state, and a function to cancel the
+	// subscription. Events are dropped if the channel's buffer
+	// (with the provided size) is full, so a slow subscriber never
+	// blocks the health checks.
+	Subscribe(size int) (events <-chan HealthEvent, cancel func())
+}
+
 type healthReporter struct {
+	mu             sync.RWMutex
 	livenssCheck   []Health
 	readinessCheck []Health
 	statusCheck    []Health
+	startupCheck   []Health
+
+	events *healthEvents
 }
 
 func NewHealthReporter() HealthReporter {
@@ -72,48 +116,130 @@ func NewHealthReporter() HealthReporter {
 		livenssCheck:   []Health{},
 		readinessCheck: []Health{},
 		statusCheck:    []Health{},
+		startupCheck:   []Health{},
+		events:         newHealthEvents(),
 	}
 }
 
 func (h *healthReporter) AddLivenessChecks(l ...Health) HealthReporter {
+	h.mu.Lock()
+	defer h.mu.Unlock()
 	h.livenssCheck = append(h.livenssCheck, l...)
 	return h
 }
 
 func (h *healthReporter) AddReadinessChecks(l ...Health) HealthReporter {
+	h.mu.Lock()
+	defer h.mu.Unlock()
 	h.readinessCheck = append(h.readinessCheck, l...)
 	return h
 }
 
 func (h *healthReporter) AddStatusChecks(l ...Health) HealthReporter {
+	h.mu.Lock()
+	defer h.mu.Unlock()
 	h.statusCheck = append(h.statusCheck, l...)
 	return h
 }
 
+func (h *healthReporter) AddStartupChecks(l ...Health) HealthReporter {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.startupCheck = append(h.startupCheck, l...)
+	return h
+}
+
 func (h *healthReporter) AddToChecks(l ...Health) HealthReporter {
 	return h.AddLivenessChecks(l...).AddReadinessChecks(l...).AddStatusChecks(l...)
 }
 
-func (h healthReporter) LivenessStatus(ctx context.Context) LivenessStatus {
-	for _, health := range h.livenssCheck {
-		if st := health.LivenessStatus(ctx); st != StatusAlive {
-			return st
+func (h *healthReporter) RemoveChecks(l ...Health) HealthReporter {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, list := range []*[]Health{&h.livenssCheck, &h.readinessCheck, &h.statusCheck, &h.startupCheck} {
+		*list = removeHealth(*list, l)
+	}
+	return h
+}
+
+// removeHealth returns the list without the removed checks.
+func removeHealth(list []Health, removed []Health) []Health {
+	res := make([]Health, 0, len(list))
+	for _, health := range list {
+		keep := true
+		for _, r := range removed {
+			if sameHealth(health, r) {
+				keep = false
+				break
+			}
+		}
+		if keep {
+			res = append(res, health)
 		}
 	}
-	return StatusAlive
+	return res
+}
+
+// sameHealth compares the checks without panicking on checks
+// with uncomparable types.
+func sameHealth(a, b Health) bool {
+	t := reflect.TypeOf(a)
+	return t == reflect.TypeOf(b) && t.Comparable() && a == b
+}
+
+// checks returns a copy of the list, so we can run checks
+// without holding the lock.
+func (h *healthReporter) checks(l *[]Health) []Health {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return append([]Health(nil), (*l)...)
 }
 
-func (h healthReporter) ReadinessStatus(ctx context.Context) ReadinessStatus {
-	for _, health := range h.readinessCheck {
-		if st := health.ReadinessStatus(ctx); st != StatusReady {
-			return st
+// LivenessStatus observes all checks, so we record state of the checks
+// after a failed one too, and returns the first failed status.
+func (h *healthReporter) LivenessStatus(ctx context.Context) LivenessStatus {
+	res := StatusAlive
+	for _, health := range h.checks(&h.livenssCheck) {
+		st := health.LivenessStatus(ctx)
+		h.events.observe(health.HealthIdentifier(), ProbeLiveness, string(st), "")
+		if st != StatusAlive && res == StatusAlive {
+			res = st
 		}
 	}
-	return StatusReady
+	return res
 }
 
-func (h healthReporter) HealthReport(ctx context.Context) HealthReport {
-	l := HealthCheck(ctx, h.statusCheck...)
+func (h *healthReporter) ReadinessStatus(ctx context.Context) ReadinessStatus {
+	res := StatusReady
+	for _, health := range h.checks(&h.readinessCheck) {
+		st := health.ReadinessStatus(ctx)
+		h.events.observe(health.HealthIdentifier(), ProbeReadiness, string(st), "")
+		if st != StatusReady && res == StatusReady {
+			res = st
+		}
+	}
+	return res
+}
+
+func (h *healthReporter) StartupStatus(ctx context.Context) StartupStatus {
+	res := StatusStarted
+	for _, health := range h.checks(&h.startupCheck) {
+		st := StatusStarted
+		if health.ReadinessStatus(ctx) != StatusReady {
+			st, res = StatusNotStarted, StatusNotStarted
+		}
+		h.events.observe(health.HealthIdentifier(), ProbeStartup, string(st), "")
+	}
+	return res
+}
+
+func (h *healthReporter) HealthReport(ctx context.Context) HealthReport {
+	l := HealthCheck(ctx, h.checks(&h.statusCheck)...)
+	for _, st := range l {
+		h.events.observe(st.Id, ProbeLiveness, string(st.Alive), st.Tags[HealthErrorTag])
+		h.events.observe(st.Id, ProbeReadiness, string(st.Ready), st.Tags[HealthErrorTag])
+	}
+
 	return HealthReport{
 		Alive:    AliveStatus(l...),
 		Ready:    ReadyStatus(l...),
@@ -121,8 +247,15 @@ func (h healthReporter) HealthReport(ctx context.Context) HealthReport {
 	}
 }
 
+func (h *healthReporter) Subscribe(size int) (<-chan HealthEvent, func()) {
+	return h.events.subscribe(size)
+}
+
 // Assertion
 var _ HealthReporter = &healthReporter{}
+var _ StartupReporter = &healthReporter{}
+var _ HealthCheckRemover = &healthReporter{}
+var _ HealthSubscriber = &healthReporter{}
 
 func HealthCheck(ctx context.Context, l ...Health) []HealthStatus {
 	// TODO: check using go routines
@@ -0,0 +1,101 @@
+package hexa
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// HealthProbe is the kind of the probe that a check's state belongs to.
+type HealthProbe string
+
+const (
+	ProbeLiveness  HealthProbe = "liveness"
+	ProbeReadiness HealthProbe = "readiness"
+	ProbeStartup   HealthProbe = "startup"
+)
+
+// HealthEvent is emitted when a check changes its state in a probe.
+// The first observation of each check has an empty Previous status.
+type HealthEvent struct {
+	Id       string      `json:"id"`
+	Probe    HealthProbe `json:"probe"`
+	Previous string      `json:"previous"`
+	Current  string      `json:"current"`
+	Reason   string      `json:"reason"`
+	Time     time.Time   `json:"time"`
+}
+
+type healthStateKey struct {
+	id    string
+	probe HealthProbe
+}
+
+// healthEvents keeps the last state of checks and emits
+// an event to the subscribers when a state changes.
+type healthEvents struct {
+	mu     sync.Mutex
+	states map[healthStateKey]string
+	subs   map[int]chan HealthEvent
+	nextID int
+}
+
+func newHealthEvents() *healthEvents {
+	return &healthEvents{
+		states: make(map[healthStateKey]string),
+		subs:   make(map[int]chan HealthEvent),
+	}
+}
+
+// observe records the check's status in the probe. reason is the reason
+// of the status, e.g., the check's error, the event has a default reason
+// if it's empty.
+func (e *healthEvents) observe(id string, probe HealthProbe, status string, reason string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	key := healthStateKey{id: id, probe: probe}
+	prev, ok := e.states[key]
+	if ok && prev == status {
+		return
+	}
+	e.states[key] = status
+
+	if reason == "" {
+		reason = fmt.Sprintf("%s check %q reported %s", probe, id, status)
+	}
+	event := HealthEvent{
+		Id:       id,
+		Probe:    probe,
+		Previous: prev,
+		Current:  status,
+		Reason:   reason,
+		Time:     time.Now(),
+	}
+	for _, ch := range e.subs {
+		select {
+		case ch <- event:
+		default: // drop the event, the subscriber is slow.
+		}
+	}
+}
+
+func (e *healthEvents) subscribe(size int) (<-chan HealthEvent, func()) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	id := e.nextID
+	e.nextID++
+	ch := make(chan HealthEvent, size)
+	e.subs[id] = ch
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			e.mu.Lock()
+			defer e.mu.Unlock()
+			delete(e.subs, id)
+			close(ch)
+		})
+	}
+}
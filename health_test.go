@@ -76,3 +76,135 @@ func TestAliveAndReadyStatus(t *testing.T) {
 	assert.Equal(t, StatusDead, AliveStatus(mixed...))
 	assert.Equal(t, StatusUnReady, ReadyStatus(mixed...))
 }
+
+func TestHealthReporter_StartupStatus(t *testing.T) {
+	ctx := context.Background()
+
+	startup := func(r HealthReporter) StartupReporter { return r.(StartupReporter) }
+
+	assert.Equal(t, StatusStarted, startup(NewHealthReporter()).StartupStatus(ctx))
+	assert.Equal(t, StatusStarted, startup(startup(NewHealthReporter()).AddStartupChecks(aliveHealth("a"))).StartupStatus(ctx))
+	assert.Equal(t, StatusNotStarted, startup(startup(NewHealthReporter()).AddStartupChecks(deadHealth("d"))).StartupStatus(ctx))
+
+	// AddToChecks doesn't register startup checks.
+	assert.Equal(t, StatusStarted, startup(NewHealthReporter().AddToChecks(deadHealth("d"))).StartupStatus(ctx))
+}
+
+func TestHealthReporter_RemoveChecks(t *testing.T) {
+	ctx := context.Background()
+	d := deadHealth("d")
+	r := NewHealthReporter().AddToChecks(aliveHealth("a"), d)
+	r = r.(StartupReporter).AddStartupChecks(d)
+	assert.Equal(t, StatusDead, r.LivenessStatus(ctx))
+
+	r = r.(HealthCheckRemover).RemoveChecks(d)
+	assert.Equal(t, StatusAlive, r.LivenessStatus(ctx))
+	assert.Equal(t, StatusReady, r.ReadinessStatus(ctx))
+	assert.Equal(t, StatusStarted, r.(StartupReporter).StartupStatus(ctx))
+	assert.Len(t, r.HealthReport(ctx).Statuses, 1)
+}
+
+func TestHealthReporter_Subscribe(t *testing.T) {
+	ctx := context.Background()
+	healthy := true
+	h := NewPingHealth(hlog.NewPrinterDriver(hlog.ErrorLevel), "db", func(context.Context) error {
+		if healthy {
+			return nil
+		}
+		return errors.New("down")
+	}, nil)
+
+	r := NewHealthReporter().AddToChecks(h)
+	events, cancel := r.(HealthSubscriber).Subscribe(10)
+
+	r.LivenessStatus(ctx)
+	r.LivenessStatus(ctx) // unchanged state emits no event.
+	healthy = false
+	r.LivenessStatus(ctx)
+
+	first := <-events
+	assert.Equal(t, "db", first.Id)
+	assert.Equal(t, ProbeLiveness, first.Probe)
+	assert.Equal(t, "", first.Previous)
+	assert.Equal(t, string(StatusAlive), first.Current)
+
+	second := <-events
+	assert.Equal(t, string(StatusAlive), second.Previous)
+	assert.Equal(t, string(StatusDead), second.Current)
+	assert.Contains(t, second.Reason, `"db"`)
+
+	select {
+	case e := <-events:
+		t.Fatalf("unexpected event: %+v", e)
+	default:
+	}
+
+	cancel()
+	cancel() // cancel is idempotent.
+	_, ok := <-events
+	assert.False(t, ok)
+}
+
+func TestHealthReporter_SubscribeReasonFromTags(t *testing.T) {
+	r := NewHealthReporter().AddStatusChecks(taggedHealth{})
+	events, cancel := r.(HealthSubscriber).Subscribe(10)
+	defer cancel()
+
+	r.HealthReport(context.Background())
+
+	for i := 0; i < 2; i++ {
+		e := <-events
+		assert.Equal(t, "boom", e.Reason)
+	}
+}
+
+func TestHealthReporter_SubscribeProbeReason(t *testing.T) {
+	ctx := context.Background()
+	r := NewHealthReporter().AddLivenessChecks(taggedHealth{}).AddReadinessChecks(taggedHealth{})
+	r = r.(StartupReporter).AddStartupChecks(taggedHealth{})
+	events, cancel := r.(HealthSubscriber).Subscribe(10)
+	defer cancel()
+
+	r.LivenessStatus(ctx)
+	r.ReadinessStatus(ctx)
+	r.(StartupReporter).StartupStatus(ctx)
+
+	// Probes don't run the check's HealthStatus again to get the reason.
+	for _, probe := range []HealthProbe{ProbeLiveness, ProbeReadiness, ProbeStartup} {
+		e := <-events
+		assert.Equal(t, probe, e.Probe)
+		assert.Equal(t, "tagged", e.Id)
+		assert.Contains(t, e.Reason, `"tagged"`)
+	}
+}
+
+func TestHealthReporter_ObservesChecksAfterFailure(t *testing.T) {
+	ctx := context.Background()
+	r := NewHealthReporter().AddToChecks(deadHealth("d"), aliveHealth("a"))
+	r = r.(StartupReporter).AddStartupChecks(deadHealth("d"), aliveHealth("a"))
+	events, cancel := r.(HealthSubscriber).Subscribe(10)
+	defer cancel()
+
+	assert.Equal(t, StatusDead, r.LivenessStatus(ctx))
+	assert.Equal(t, StatusUnReady, r.ReadinessStatus(ctx))
+	assert.Equal(t, StatusNotStarted, r.(StartupReporter).StartupStatus(ctx))
+
+	for _, probe := range []HealthProbe{ProbeLiveness, ProbeReadiness, ProbeStartup} {
+		for _, id := range []string{"d", "a"} {
+			e := <-events
+			assert.Equal(t, probe, e.Probe)
+			assert.Equal(t, id, e.Id)
+		}
+	}
+}
+
+type taggedHealth struct{}
+
+func (taggedHealth) HealthIdentifier() string                      { return "tagged" }
+func (taggedHealth) LivenessStatus(context.Context) LivenessStatus { return StatusDead }
+func (taggedHealth) ReadinessStatus(context.Context) ReadinessStatus {
+	return StatusUnReady
+}
+func (taggedHealth) HealthStatus(context.Context) HealthStatus {
+	return HealthStatus{Id: "tagged", Alive: StatusDead, Ready: StatusUnReady, Tags: map[string]string{HealthErrorTag: "boom"}}
+}
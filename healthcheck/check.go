@@ -0,0 +1,113 @@
+package healthcheck
+
+import (
+	"context"
+	"time"
+
+	"github.com/kamva/hexa"
+	"github.com/kamva/hexa/hlog"
+)
+
+// Options contains the options that are shared by all checks.
+type Options struct {
+	// Identifier is the health identifier. each check has its own default value.
+	Identifier string
+	Tags       map[string]string
+	// Timeout limits each run of the check. zero value means no timeout.
+	Timeout time.Duration
+	// ReadinessOnly specifies that a failed check just makes the app
+	// unready and doesn't report it as dead. Use it for external
+	// dependencies that restarting the app can not fix them.
+	ReadinessOnly bool
+	// Logger is optional, default value is the global logger.
+	Logger hlog.Logger
+}
+
+// CheckFunc checks the health of something and returns an
+// error if it's not healthy.
+type CheckFunc func(ctx context.Context) error
+
+type check struct {
+	o  Options
+	fn CheckFunc
+}
+
+// New returns a new Health which uses the provided check function.
+// all checks in this package are built on top of it.
+func New(o Options, fn CheckFunc) hexa.Health {
+	if o.Logger == nil {
+		o.Logger = hlog.GlobalLogger()
+	}
+
+	return &check{o: o, fn: fn}
+}
+
+func (c *check) run(ctx context.Context) error {
+	if c.o.Timeout != 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.o.Timeout)
+		defer cancel()
+	}
+
+	err := c.fn(ctx)
+	if err != nil {
+		c.o.Logger.Error("health check failed", hlog.String("health_identifier", c.o.Identifier), hlog.Err(err))
+	}
+	return err
+}
+
+func (c *check) HealthIdentifier() string {
+	return c.o.Identifier
+}
+
+func (c *check) LivenessStatus(ctx context.Context) hexa.LivenessStatus {
+	return c.liveness(c.run(ctx))
+}
+
+func (c *check) ReadinessStatus(ctx context.Context) hexa.ReadinessStatus {
+	return readiness(c.run(ctx))
+}
+
+func (c *check) HealthStatus(ctx context.Context) hexa.HealthStatus {
+	err := c.run(ctx)
+
+	tags := c.o.Tags
+	if err != nil {
+		tags = make(map[string]string, len(c.o.Tags)+1)
+		for k, v := range c.o.Tags {
+			tags[k] = v
+		}
+		tags[hexa.HealthErrorTag] = err.Error()
+	}
+
+	return hexa.HealthStatus{
+		Id:    c.HealthIdentifier(),
+		Alive: c.liveness(err),
+		Ready: readiness(err),
+		Tags:  tags,
+	}
+}
+
+func (c *check) liveness(err error) hexa.LivenessStatus {
+	if err != nil && !c.o.ReadinessOnly {
+		return hexa.StatusDead
+	}
+	return hexa.StatusAlive
+}
+
+func readiness(err error) hexa.ReadinessStatus {
+	if err != nil {
+		return hexa.StatusUnReady
+	}
+	return hexa.StatusReady
+}
+
+// withDefaultIdentifier sets the identifier if it's empty.
+func withDefaultIdentifier(o Options, id string) Options {
+	if o.Identifier == "" {
+		o.Identifier = id
+	}
+	return o
+}
+
+var _ hexa.Health = &check{}
@@ -0,0 +1,26 @@
+package healthcheck
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kamva/hexa"
+	"github.com/kamva/tracer"
+)
+
+// NewDiskSpace returns a health check which fails when free disk
+// space (available to unprivileged users) of the filesystem that
+// contains the path is less than minFreeBytes.
+func NewDiskSpace(path string, minFreeBytes uint64, o Options) hexa.Health {
+	return New(withDefaultIdentifier(o, "disk:"+path), func(context.Context) error {
+		free, err := freeDiskSpace(path)
+		if err != nil {
+			return tracer.Trace(err)
+		}
+
+		if free < minFreeBytes {
+			return fmt.Errorf("free disk space is %d bytes, minimum is %d bytes", free, minFreeBytes)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,12 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd)
+
+package healthcheck
+
+import (
+	"errors"
+	"runtime"
+)
+
+func freeDiskSpace(string) (uint64, error) {
+	return 0, errors.New("disk space health check is not supported on " + runtime.GOOS)
+}
@@ -0,0 +1,28 @@
+package healthcheck
+
+import (
+	"context"
+	"errors"
+
+	"github.com/kamva/hexa"
+	"github.com/kamva/tracer"
+)
+
+// NewDLM returns a health check which acquires and releases a probe
+// lock with the provided key.
+// If another instance holds the probe lock, the DLM is considered
+// healthy, because it's working.
+func NewDLM(dlm hexa.DLM, key string, o Options) hexa.Health {
+	return New(withDefaultIdentifier(o, "dlm"), func(ctx context.Context) error {
+		mutex := dlm.NewMutex(key)
+		err := mutex.TryLock(ctx)
+		if errors.Is(err, hexa.ErrLockAlreadyAcquired) {
+			return nil
+		}
+		if err != nil {
+			return tracer.Trace(err)
+		}
+
+		return tracer.Trace(mutex.Unlock(ctx))
+	})
+}
@@ -0,0 +1,5 @@
+// Package healthcheck provides ready-made hexa.Health implementations for
+// common dependencies and resources (HTTP endpoints, TCP addresses, Redis,
+// disk space, runtime stats and distributed locks), ready to be registered
+// on a hexa.HealthReporter.
+package healthcheck
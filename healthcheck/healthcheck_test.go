@@ -0,0 +1,164 @@
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kamva/hexa"
+	"github.com/kamva/hexa/hlog"
+	"github.com/kamva/hexa/hurl"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var quiet = Options{Logger: hlog.NewPrinterDriver(hlog.ErrorLevel + 1)}
+
+func withID(id string) Options {
+	o := quiet
+	o.Identifier = id
+	return o
+}
+
+func TestNew_FailedCheck(t *testing.T) {
+	ctx := context.Background()
+	o := withID("x")
+	o.Tags = map[string]string{"k": "v"}
+	h := New(o, func(context.Context) error { return errors.New("down") })
+
+	assert.Equal(t, "x", h.HealthIdentifier())
+	assert.Equal(t, hexa.StatusDead, h.LivenessStatus(ctx))
+	assert.Equal(t, hexa.StatusUnReady, h.ReadinessStatus(ctx))
+
+	st := h.HealthStatus(ctx)
+	assert.Equal(t, "v", st.Tags["k"])
+	assert.Equal(t, "down", st.Tags[hexa.HealthErrorTag])
+	assert.NotContains(t, o.Tags, hexa.HealthErrorTag) // provided tags must not be mutated.
+}
+
+func TestNew_ReadinessOnly(t *testing.T) {
+	ctx := context.Background()
+	o := withID("x")
+	o.ReadinessOnly = true
+	h := New(o, func(context.Context) error { return errors.New("down") })
+
+	assert.Equal(t, hexa.StatusAlive, h.LivenessStatus(ctx))
+	assert.Equal(t, hexa.StatusUnReady, h.ReadinessStatus(ctx))
+}
+
+func TestNew_Timeout(t *testing.T) {
+	o := withID("x")
+	o.Timeout = 10 * time.Millisecond
+	h := New(o, func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	assert.Equal(t, hexa.StatusDead, h.LivenessStatus(context.Background()))
+}
+
+func TestNewHTTP(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/health" {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer ts.Close()
+
+	cli, err := hurl.NewClient(ts.URL, hurl.LogModeNone)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	ok := NewHTTP(cli, "/health", HTTPOptions{Options: quiet, ExpectedStatus: http.StatusNoContent})
+	assert.Equal(t, "http:/health", ok.HealthIdentifier())
+	assert.Equal(t, hexa.StatusAlive, ok.LivenessStatus(ctx))
+
+	bad := NewHTTP(cli, "/other", HTTPOptions{Options: quiet})
+	assert.Equal(t, hexa.StatusDead, bad.LivenessStatus(ctx))
+}
+
+func TestNewTCP(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := ln.Addr().String()
+
+	ctx := context.Background()
+	assert.Equal(t, hexa.StatusAlive, NewTCP(addr, quiet).LivenessStatus(ctx))
+
+	require.NoError(t, ln.Close())
+	assert.Equal(t, hexa.StatusDead, NewTCP(addr, quiet).LivenessStatus(ctx))
+}
+
+func TestNewRedis_Unreachable(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := ln.Addr().String()
+	require.NoError(t, ln.Close())
+
+	cli := redis.NewClient(&redis.Options{Addr: addr, MaxRetries: -1})
+	defer cli.Close()
+
+	h := NewRedis(cli, quiet)
+	assert.Equal(t, "redis", h.HealthIdentifier())
+	assert.Equal(t, hexa.StatusDead, h.LivenessStatus(context.Background()))
+}
+
+func TestNewDiskSpace(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	assert.Equal(t, hexa.StatusAlive, NewDiskSpace(dir, 1, quiet).LivenessStatus(ctx))
+	assert.Equal(t, hexa.StatusDead, NewDiskSpace(dir, 1<<62, quiet).LivenessStatus(ctx))
+}
+
+func TestNewGoroutineCountAndHeapSize(t *testing.T) {
+	ctx := context.Background()
+
+	assert.Equal(t, hexa.StatusAlive, NewGoroutineCount(1<<20, quiet).LivenessStatus(ctx))
+	assert.Equal(t, hexa.StatusDead, NewGoroutineCount(0, quiet).LivenessStatus(ctx))
+
+	assert.Equal(t, hexa.StatusAlive, NewHeapSize(1<<62, quiet).LivenessStatus(ctx))
+	assert.Equal(t, hexa.StatusDead, NewHeapSize(1, quiet).LivenessStatus(ctx))
+}
+
+type fakeMutex struct {
+	lockErr  error
+	unlocked bool
+}
+
+func (m *fakeMutex) Lock(ctx context.Context) error                   { return m.TryLock(ctx) }
+func (m *fakeMutex) TryLock(context.Context) error                    { return m.lockErr }
+func (m *fakeMutex) Unlock(context.Context) error                     { m.unlocked = true; return nil }
+func (m *fakeMutex) NewMutex(string) hexa.Mutex                       { return m }
+func (m *fakeMutex) NewMutexWithOptions(hexa.MutexOptions) hexa.Mutex { return m }
+func (m *fakeMutex) NewMutexWithTTL(string, time.Duration) hexa.Mutex { return m }
+
+func TestNewDLM(t *testing.T) {
+	ctx := context.Background()
+
+	m := &fakeMutex{}
+	assert.Equal(t, hexa.StatusAlive, NewDLM(m, "probe", quiet).LivenessStatus(ctx))
+	assert.True(t, m.unlocked)
+
+	// Lock held by another instance means the DLM works.
+	m = &fakeMutex{lockErr: hexa.ErrLockAlreadyAcquired}
+	assert.Equal(t, hexa.StatusAlive, NewDLM(m, "probe", quiet).LivenessStatus(ctx))
+
+	m = &fakeMutex{lockErr: errors.New("connection refused")}
+	assert.Equal(t, hexa.StatusDead, NewDLM(m, "probe", quiet).LivenessStatus(ctx))
+}
+
+func TestChecks_CanBeRegistered(t *testing.T) {
+	r := hexa.NewHealthReporter().AddToChecks(
+		NewGoroutineCount(1<<20, quiet),
+		NewHeapSize(1<<62, quiet),
+	)
+	assert.Equal(t, hexa.StatusReady, r.ReadinessStatus(context.Background()))
+}
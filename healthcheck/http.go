@@ -0,0 +1,44 @@
+package healthcheck
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/kamva/hexa"
+	"github.com/kamva/hexa/hurl"
+	"github.com/kamva/tracer"
+)
+
+type HTTPOptions struct {
+	Options
+	// ExpectedStatus is the expected response status code, default value is 200.
+	ExpectedStatus int
+	// RequestOptions will be applied on the health check request (e.g., auth headers).
+	RequestOptions []hurl.RequestOption
+}
+
+// NewHTTP returns a health check which sends a GET request to the url
+// and checks the response status code. The url can be relative to
+// the client's base url.
+func NewHTTP(cli *hurl.Client, url string, o HTTPOptions) hexa.Health {
+	if o.ExpectedStatus == 0 {
+		o.ExpectedStatus = http.StatusOK
+	}
+
+	return New(withDefaultIdentifier(o.Options, "http:"+url), func(ctx context.Context) error {
+		opts := append([]hurl.RequestOption{hurl.WithContext(ctx)}, o.RequestOptions...)
+		resp, err := cli.Get(url, opts...)
+		if err != nil {
+			return tracer.Trace(err)
+		}
+		defer resp.Body.Close()
+		_, _ = io.Copy(io.Discard, resp.Body)
+
+		if resp.StatusCode != o.ExpectedStatus {
+			return fmt.Errorf("unexpected status code %d, expected %d", resp.StatusCode, o.ExpectedStatus)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,16 @@
+package healthcheck
+
+import (
+	"context"
+
+	"github.com/kamva/hexa"
+	"github.com/kamva/tracer"
+	"github.com/redis/go-redis/v9"
+)
+
+// NewRedis returns a health check which pings the redis server.
+func NewRedis(cli redis.UniversalClient, o Options) hexa.Health {
+	return New(withDefaultIdentifier(o, "redis"), func(ctx context.Context) error {
+		return tracer.Trace(cli.Ping(ctx).Err())
+	})
+}
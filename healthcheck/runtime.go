@@ -0,0 +1,35 @@
+package healthcheck
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+
+	"github.com/kamva/hexa"
+)
+
+// NewGoroutineCount returns a health check which fails when number
+// of goroutines exceeds maxGoroutines.
+func NewGoroutineCount(maxGoroutines int, o Options) hexa.Health {
+	return New(withDefaultIdentifier(o, "goroutines"), func(context.Context) error {
+		if n := runtime.NumGoroutine(); n > maxGoroutines {
+			return fmt.Errorf("number of goroutines is %d, maximum is %d", n, maxGoroutines)
+		}
+		return nil
+	})
+}
+
+// NewHeapSize returns a health check which fails when allocated
+// heap bytes exceeds maxBytes.
+// Please note it calls runtime.ReadMemStats which stops the world,
+// so don't run it too often.
+func NewHeapSize(maxBytes uint64, o Options) hexa.Health {
+	return New(withDefaultIdentifier(o, "heap"), func(context.Context) error {
+		var st runtime.MemStats
+		runtime.ReadMemStats(&st)
+		if st.HeapAlloc > maxBytes {
+			return fmt.Errorf("heap size is %d bytes, maximum is %d bytes", st.HeapAlloc, maxBytes)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,21 @@
+package healthcheck
+
+import (
+	"context"
+	"net"
+
+	"github.com/kamva/hexa"
+	"github.com/kamva/tracer"
+)
+
+// NewTCP returns a health check which dials the tcp address.
+func NewTCP(addr string, o Options) hexa.Health {
+	return New(withDefaultIdentifier(o, "tcp:"+addr), func(ctx context.Context) error {
+		var d net.Dialer
+		conn, err := d.DialContext(ctx, "tcp", addr)
+		if err != nil {
+			return tracer.Trace(err)
+		}
+		return tracer.Trace(conn.Close())
+	})
+}
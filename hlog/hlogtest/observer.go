@@ -0,0 +1,212 @@
+// Package hlogtest provides an observer log driver which records log
+// entries in memory, so tests can assert on what was logged.
+//
+// Example:
+//
+//	func TestReport(t *testing.T) {
+//		l, logs := hlogtest.New(hlog.DebugLevel)
+//		doSomething(l)
+//		assert.Equal(t, 1, logs.FilterLevel(hlog.ErrorLevel).Len())
+//	}
+package hlogtest
+
+import (
+	"context"
+	"reflect"
+	"sync"
+
+	"github.com/kamva/hexa/hlog"
+)
+
+// Entry is a logged entry.
+type Entry struct {
+	Level   hlog.Level
+	Message string
+	// Fields are fields of the logger and the entry, decoded by
+	// hlog.FieldToKeyVal.
+	Fields map[string]any
+}
+
+// Has returns true if the entry has the field with the value.
+func (e Entry) Has(key string, val any) bool {
+	v, ok := e.Fields[key]
+	return ok && reflect.DeepEqual(v, val)
+}
+
+// ObservedLogs is a concurrency-safe collection of logged entries.
+type ObservedLogs struct {
+	mu      sync.RWMutex
+	entries []Entry
+}
+
+func (o *ObservedLogs) add(e Entry) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.entries = append(o.entries, e)
+}
+
+// Len returns the number of entries.
+func (o *ObservedLogs) Len() int {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return len(o.entries)
+}
+
+// All returns copy of the entries.
+func (o *ObservedLogs) All() []Entry {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	entries := make([]Entry, len(o.entries))
+	copy(entries, o.entries)
+	return entries
+}
+
+// Messages returns messages of the entries.
+func (o *ObservedLogs) Messages() []string {
+	entries := o.All()
+	msgs := make([]string, len(entries))
+	for i, e := range entries {
+		msgs[i] = e.Message
+	}
+	return msgs
+}
+
+// Reset removes all entries.
+func (o *ObservedLogs) Reset() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.entries = nil
+}
+
+// Filter returns entries which match the function.
+func (o *ObservedLogs) Filter(fn func(e Entry) bool) *ObservedLogs {
+	filtered := &ObservedLogs{}
+	for _, e := range o.All() {
+		if fn(e) {
+			filtered.entries = append(filtered.entries, e)
+		}
+	}
+	return filtered
+}
+
+// FilterLevel returns entries with the level.
+func (o *ObservedLogs) FilterLevel(lvl hlog.Level) *ObservedLogs {
+	return o.Filter(func(e Entry) bool { return e.Level == lvl })
+}
+
+// FilterMessage returns entries with the message.
+func (o *ObservedLogs) FilterMessage(msg string) *ObservedLogs {
+	return o.Filter(func(e Entry) bool { return e.Message == msg })
+}
+
+// FilterField returns entries which have the field with the value.
+func (o *ObservedLogs) FilterField(key string, val any) *ObservedLogs {
+	return o.Filter(func(e Entry) bool { return e.Has(key, val) })
+}
+
+// FilterFieldKey returns entries which have the field.
+func (o *ObservedLogs) FilterFieldKey(key string) *ObservedLogs {
+	return o.Filter(func(e Entry) bool {
+		_, ok := e.Fields[key]
+		return ok
+	})
+}
+
+type observerLogger struct {
+	logs  *ObservedLogs
+	level *hlog.AtomicLevel
+	with  []hlog.Field
+	name  string
+}
+
+func (l *observerLogger) Core() any {
+	return l.logs
+}
+
+func (l *observerLogger) Enabled(lvl hlog.Level) bool {
+	return l.Level().CanLog(lvl)
+}
+
+func (l *observerLogger) Level() hlog.Level {
+	return hlog.EffectiveLevel(l.name, l.level.Level())
+}
+
+// SetLevel sets level of the logger, or level of its name if it's a
+// named logger.
+func (l *observerLogger) SetLevel(lvl hlog.Level) {
+	if l.name != "" {
+		hlog.SetNamedLevel(l.name, lvl)
+		return
+	}
+	l.level.SetLevel(lvl)
+}
+
+func (l *observerLogger) Named(name string) hlog.Logger {
+	clone := l.clone(nil)
+	clone.name = hlog.JoinNames(l.name, name)
+	return clone
+}
+
+func (l *observerLogger) clone(fields []hlog.Field) *observerLogger {
+	with := make([]hlog.Field, 0, len(l.with)+len(fields))
+	with = append(append(with, l.with...), fields...)
+	return &observerLogger{logs: l.logs, level: l.level, with: with, name: l.name}
+}
+
+func (l *observerLogger) WithCtx(ctx context.Context, fields ...hlog.Field) hlog.Logger {
+	clone := l.clone(nil)
+	clone.with = append(hlog.ReplaceTraceFields(ctx, clone.with), fields...)
+	return clone
+}
+
+func (l *observerLogger) With(fields ...hlog.Field) hlog.Logger {
+	return l.clone(fields)
+}
+
+func (l *observerLogger) log(lvl hlog.Level, msg string, fields []hlog.Field) {
+	if !l.Enabled(lvl) {
+		return
+	}
+
+	m := make(map[string]any, len(l.with)+len(fields)+1)
+	for _, f := range append(l.with[:len(l.with):len(l.with)], fields...) {
+		k, v := hlog.FieldToKeyVal(f)
+		m[k] = v
+	}
+	if l.name != "" {
+		m[hlog.LoggerNameKey] = l.name
+	}
+	l.logs.add(Entry{Level: lvl, Message: msg, Fields: m})
+}
+
+func (l *observerLogger) Debug(msg string, fields ...hlog.Field) {
+	l.log(hlog.DebugLevel, msg, fields)
+}
+
+func (l *observerLogger) Info(msg string, fields ...hlog.Field) {
+	l.log(hlog.InfoLevel, msg, fields)
+}
+
+func (l *observerLogger) Message(msg string, fields ...hlog.Field) {
+	l.log(hlog.InfoLevel, msg, fields)
+}
+
+func (l *observerLogger) Warn(msg string, fields ...hlog.Field) {
+	l.log(hlog.WarnLevel, msg, fields)
+}
+
+func (l *observerLogger) Error(msg string, fields ...hlog.Field) {
+	l.log(hlog.ErrorLevel, msg, fields)
+}
+
+// New returns an observer logger which records entries of the level and
+// higher levels, and the entries which it records.
+func New(lvl hlog.Level) (hlog.Logger, *ObservedLogs) {
+	logs := &ObservedLogs{}
+	return &observerLogger{logs: logs, level: hlog.NewAtomicLevel(lvl)}, logs
+}
+
+// Assert observerLogger implements hexa Logger.
+var _ hlog.Logger = &observerLogger{}
+var _ hlog.LevelSetter = &observerLogger{}
+var _ hlog.Namer = &observerLogger{}
@@ -0,0 +1,49 @@
+package hlogtest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kamva/hexa/hlog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestObserver(t *testing.T) {
+	l, logs := New(hlog.InfoLevel)
+	child := l.With(hlog.String("a", "b")).WithCtx(context.Background(), hlog.Int("n", 1))
+
+	l.Debug("hidden")
+	l.Info("info")
+	child.Warn("warn", hlog.Bool("retry", true))
+	child.Error("failed", hlog.Err(errors.New("boom")))
+	hlog.NamedLogger(l, "mongo").Message("named")
+
+	require.Equal(t, 4, logs.Len())
+	assert.Equal(t, []string{"info", "warn", "failed", "named"}, logs.Messages())
+	assert.Equal(t, Entry{
+		Level:   hlog.WarnLevel,
+		Message: "warn",
+		Fields:  map[string]any{"a": "b", "n": int64(1), "retry": true},
+	}, logs.FilterLevel(hlog.WarnLevel).All()[0])
+
+	assert.Equal(t, 2, logs.FilterField("a", "b").Len())
+	assert.Equal(t, 1, logs.FilterFieldKey("error").FilterMessage("failed").Len())
+	assert.True(t, logs.FilterMessage("failed").All()[0].Has("error", "boom"))
+	assert.Equal(t, 1, logs.FilterField(hlog.LoggerNameKey, "mongo").Len())
+	assert.Zero(t, logs.FilterField("a", "c").Len())
+
+	logs.Reset()
+	assert.Zero(t, logs.Len())
+	child.Info("after reset")
+	assert.Equal(t, 1, logs.Len())
+}
+
+func TestObserver_Level(t *testing.T) {
+	l, logs := New(hlog.InfoLevel)
+	child := l.With()
+	l.(hlog.LevelSetter).SetLevel(hlog.DebugLevel)
+	child.Debug("shown")
+	assert.Equal(t, 1, logs.FilterLevel(hlog.DebugLevel).Len())
+}
@@ -2,6 +2,7 @@ package hlog
 
 import (
 	"fmt"
+	"sync/atomic"
 
 	"go.uber.org/zap/zapcore"
 )
@@ -70,3 +71,54 @@ func ZapLevel(l Level) zapcore.Level {
 	}
 	return zl
 }
+
+// LevelFromZap converts a zap level to the level which covers it,
+// e.g., zapcore.FatalLevel is ErrorLevel.
+func LevelFromZap(l zapcore.Level) Level {
+	switch {
+	case l <= zapcore.DebugLevel:
+		return DebugLevel
+	case l == zapcore.InfoLevel:
+		return InfoLevel
+	case l == zapcore.WarnLevel:
+		return WarnLevel
+	}
+	return ErrorLevel
+}
+
+// LevelSetter is implemented by loggers which can change their level at
+// runtime. Loggers derived from a logger using With and WithCtx share its
+// level.
+type LevelSetter interface {
+	// Level returns the current level of the logger.
+	Level() Level
+
+	// SetLevel changes the level of the logger.
+	SetLevel(lvl Level)
+}
+
+// AtomicLevel is a level which is safe to change concurrently, drivers
+// share it between a logger and its derived loggers.
+type AtomicLevel struct {
+	l int32
+}
+
+// NewAtomicLevel returns new atomic level set to the level.
+func NewAtomicLevel(lvl Level) *AtomicLevel {
+	return &AtomicLevel{l: int32(lvl)}
+}
+
+func (l *AtomicLevel) Level() Level {
+	return Level(atomic.LoadInt32(&l.l))
+}
+
+func (l *AtomicLevel) SetLevel(lvl Level) {
+	atomic.StoreInt32(&l.l, int32(lvl))
+}
+
+// CanLog returns true if the current level covers the target level.
+func (l *AtomicLevel) CanLog(target Level) bool {
+	return l.Level().CanLog(target)
+}
+
+var _ LevelSetter = &AtomicLevel{}
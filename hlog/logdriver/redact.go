@@ -0,0 +1,130 @@
+package logdriver
+
+import (
+	"context"
+
+	"github.com/kamva/hexa"
+	"github.com/kamva/hexa/hlog"
+	"github.com/kamva/tracer"
+)
+
+type redactingLogger struct {
+	hlog.Logger
+	r *hlog.Redactor
+}
+
+func (l *redactingLogger) WithCtx(ctx context.Context, fields ...hlog.Field) hlog.Logger {
+	return newRedactingLogger(l.Logger.WithCtx(ctx, l.r.Redact(fields)...), l.r)
+}
+
+func (l *redactingLogger) With(fields ...hlog.Field) hlog.Logger {
+	return newRedactingLogger(l.Logger.With(l.r.Redact(fields)...), l.r)
+}
+
+func (l *redactingLogger) Named(name string) hlog.Logger {
+	return newRedactingLogger(hlog.NamedLogger(l.Logger, name), l.r)
+}
+
+func (l *redactingLogger) Debug(msg string, fields ...hlog.Field) {
+	if !l.Enabled(hlog.DebugLevel) {
+		return
+	}
+	l.Logger.Debug(msg, l.r.Redact(fields)...)
+}
+
+func (l *redactingLogger) Info(msg string, fields ...hlog.Field) {
+	if !l.Enabled(hlog.InfoLevel) {
+		return
+	}
+	l.Logger.Info(msg, l.r.Redact(fields)...)
+}
+
+func (l *redactingLogger) Message(msg string, fields ...hlog.Field) {
+	if !l.Enabled(hlog.InfoLevel) {
+		return
+	}
+	l.Logger.Message(msg, l.r.Redact(fields)...)
+}
+
+func (l *redactingLogger) Warn(msg string, fields ...hlog.Field) {
+	if !l.Enabled(hlog.WarnLevel) {
+		return
+	}
+	l.Logger.Warn(msg, l.r.Redact(fields)...)
+}
+
+func (l *redactingLogger) Error(msg string, fields ...hlog.Field) {
+	if !l.Enabled(hlog.ErrorLevel) {
+		return
+	}
+	l.Logger.Error(msg, l.r.Redact(fields)...)
+}
+
+// LoggerByName returns the logger with the name in the stack if the
+// logger is a stack of loggers, otherwise it returns nil.
+func (l *redactingLogger) LoggerByName(name string) hlog.Logger {
+	if s, ok := l.Logger.(hlog.Stack); ok {
+		return s.LoggerByName(name)
+	}
+	return nil
+}
+
+// LoggerNames returns names of the loggers in the stack if the logger is
+// a stack of loggers.
+func (l *redactingLogger) LoggerNames() []string {
+	if s, ok := l.Logger.(hlog.Stack); ok {
+		return s.LoggerNames()
+	}
+	return nil
+}
+
+func (l *redactingLogger) Boot() error {
+	if bootable, ok := l.Logger.(hexa.Bootable); ok {
+		return tracer.Trace(bootable.Boot())
+	}
+	return nil
+}
+
+func (l *redactingLogger) Shutdown(ctx context.Context) error {
+	if s, ok := l.Logger.(hexa.Shutdownable); ok {
+		return tracer.Trace(s.Shutdown(ctx))
+	}
+	return nil
+}
+
+// leveledRedactingLogger is the redacting logger of loggers which can
+// change their level.
+type leveledRedactingLogger struct {
+	*redactingLogger
+}
+
+func (l *leveledRedactingLogger) Level() hlog.Level {
+	return l.Logger.(hlog.LevelSetter).Level()
+}
+
+func (l *leveledRedactingLogger) SetLevel(lvl hlog.Level) {
+	l.Logger.(hlog.LevelSetter).SetLevel(lvl)
+}
+
+func newRedactingLogger(l hlog.Logger, r *hlog.Redactor) hlog.Logger {
+	rl := &redactingLogger{Logger: l, r: r}
+	if _, ok := l.(hlog.LevelSetter); ok {
+		return &leveledRedactingLogger{rl}
+	}
+	return rl
+}
+
+// NewRedactingDriver returns a logger which redacts sensitive values of
+// fields before passing them to the logger. Use StackOptions.Redact for
+// the stacked logger.
+func NewRedactingDriver(l hlog.Logger, o hlog.RedactOptions) hlog.Logger {
+	return newRedactingLogger(l, hlog.NewRedactor(o))
+}
+
+// Assert redactingLogger implements hexa Logger.
+var _ hlog.Logger = &redactingLogger{}
+var _ hlog.Namer = &redactingLogger{}
+var _ hlog.Stack = &redactingLogger{}
+var _ hexa.Bootable = &redactingLogger{}
+var _ hexa.Shutdownable = &redactingLogger{}
+var _ hlog.LevelSetter = &leveledRedactingLogger{}
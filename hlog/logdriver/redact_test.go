@@ -0,0 +1,67 @@
+package logdriver
+
+import (
+	"testing"
+
+	"github.com/kamva/hexa/hlog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestRedactingLogger(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	l := NewRedactingDriver(NewZapDriver(zap.New(core)), hlog.RedactOptions{})
+	require.Implements(t, (*hlog.LevelSetter)(nil), l)
+
+	hlog.NamedLogger(l, "auth").With(hlog.String("token", "t")).Info("login",
+		hlog.String("email", "a@b.com"),
+		hlog.Any("req", map[string]string{"password": "p"}),
+	)
+	require.Equal(t, 1, logs.Len())
+	assert.Equal(t, map[string]any{
+		"token":            hlog.Redacted,
+		"email":            hlog.Redacted,
+		"req":              map[string]any{"password": hlog.Redacted},
+		hlog.LoggerNameKey: "auth",
+	}, logs.All()[0].ContextMap())
+}
+
+func TestStackedLogger_Redact(t *testing.T) {
+	l, err := NewStackLoggerDriver([]string{ZapLogger}, StackOptions{
+		Level:     hlog.InfoLevel,
+		ZapConfig: DefaultZapConfig(false, zapcore.InfoLevel, "json"),
+		Redact:    &hlog.RedactOptions{},
+	})
+	require.NoError(t, err)
+	assert.NotNil(t, l.(*stackedLogger).redactor)
+
+	core, logs := observer.New(zapcore.DebugLevel)
+	l.(*stackedLogger).stack[ZapLogger] = NewZapDriver(zap.New(core))
+	l.With(hlog.String("api_key", "k")).Error("failed", hlog.String("jwt", "eyJhbGciOiJIUzI1NiJ9.eyJzdWIiOiIxIn0.c2ln"))
+	require.Equal(t, 1, logs.Len())
+	assert.Equal(t, map[string]any{"api_key": hlog.Redacted, "jwt": hlog.Redacted}, logs.All()[0].ContextMap())
+}
+
+func TestRedactingLogger_DisabledLevel(t *testing.T) {
+	core, _ := observer.New(zapcore.InfoLevel)
+	l := NewRedactingDriver(NewZapDriver(zap.New(core)), hlog.RedactOptions{})
+
+	marshaled := false
+	l.Debug("query", zap.Object("q", zapcore.ObjectMarshalerFunc(func(zapcore.ObjectEncoder) error {
+		marshaled = true
+		return nil
+	})))
+	assert.False(t, marshaled, "it must not redact entries of disabled levels")
+}
+
+func TestRedactingLogger_Stack(t *testing.T) {
+	zl := NewZapDriver(zap.New(zapcore.NewNopCore()))
+	l := NewRedactingDriver(NewStackLoggerDriverWith(hlog.InfoLevel, map[string]hlog.Logger{"zap": zl}), hlog.RedactOptions{})
+
+	require.Implements(t, (*hlog.Stack)(nil), l)
+	assert.Equal(t, []string{"zap"}, l.(hlog.Stack).LoggerNames())
+	assert.NotNil(t, l.(hlog.Stack).LoggerByName("zap"))
+}
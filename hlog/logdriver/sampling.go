@@ -0,0 +1,290 @@
+package logdriver
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/kamva/hexa"
+	"github.com/kamva/hexa/hlog"
+	"github.com/kamva/tracer"
+)
+
+// SamplingBudget is the number of entries of a message which we log in
+// each interval.
+type SamplingBudget struct {
+	// First is the number of entries which we log in each interval.
+	First int
+	// Thereafter is the sampling rate after the first entries, we log
+	// every Thereafter-th entry. zero value drops all of them.
+	Thereafter int
+}
+
+type SamplingOptions struct {
+	// Interval is the sampling interval, default value is one second.
+	Interval time.Duration
+
+	// Budget is the budget of each message per level, default value
+	// is 100 first entries and then every 100th entry.
+	Budget SamplingBudget
+
+	// Levels overrides the budget of the levels.
+	Levels map[hlog.Level]SamplingBudget
+
+	// SampleErrors samples errors too, errors are exempt by default.
+	SampleErrors bool
+
+	// SummaryInterval is the interval of logging the number of dropped
+	// entries, default value is one minute.
+	SummaryInterval time.Duration
+}
+
+func (o SamplingOptions) budget(lvl hlog.Level) SamplingBudget {
+	if b, ok := o.Levels[lvl]; ok {
+		return b
+	}
+	return o.Budget
+}
+
+type sampleKey struct {
+	lvl hlog.Level
+	msg string
+}
+
+// sampler samples entries of a logger and all loggers derived from it.
+type sampler struct {
+	o SamplingOptions
+	// logger is the logger which we log the summaries to.
+	logger hlog.Logger
+
+	mu      sync.Mutex
+	resetAt time.Time
+	counts  map[sampleKey]int
+	dropped map[hlog.Level]int64
+
+	done     chan struct{}
+	stopOnce sync.Once
+	stopped  chan struct{}
+}
+
+func newSampler(l hlog.Logger, o SamplingOptions) *sampler {
+	s := &sampler{
+		o:       o,
+		logger:  l,
+		counts:  make(map[sampleKey]int),
+		dropped: make(map[hlog.Level]int64),
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	go s.summarize()
+	return s
+}
+
+// sample returns true if we should log the entry.
+func (s *sampler) sample(lvl hlog.Level, msg string) bool {
+	if lvl == hlog.ErrorLevel && !s.o.SampleErrors {
+		return true
+	}
+	b := s.o.budget(lvl)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if now := time.Now(); !now.Before(s.resetAt) {
+		s.counts = make(map[sampleKey]int)
+		s.resetAt = now.Add(s.o.Interval)
+	}
+
+	k := sampleKey{lvl: lvl, msg: msg}
+	n := s.counts[k] + 1
+	s.counts[k] = n
+	if n <= b.First || (b.Thereafter > 0 && (n-b.First)%b.Thereafter == 0) {
+		return true
+	}
+	s.dropped[lvl]++
+	return false
+}
+
+func (s *sampler) summarize() {
+	defer close(s.stopped)
+	t := time.NewTicker(s.o.SummaryInterval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-t.C:
+			s.logSummary()
+		case <-s.done:
+			s.logSummary()
+			return
+		}
+	}
+}
+
+// logSummary logs the number of dropped entries since the last summary.
+func (s *sampler) logSummary() {
+	s.mu.Lock()
+	dropped := s.dropped
+	s.dropped = make(map[hlog.Level]int64)
+	s.mu.Unlock()
+
+	var total int64
+	fields := make([]hlog.Field, 0, len(dropped)+1)
+	for _, lvl := range []hlog.Level{hlog.DebugLevel, hlog.InfoLevel, hlog.WarnLevel, hlog.ErrorLevel} {
+		if n := dropped[lvl]; n != 0 {
+			total += n
+			fields = append(fields, hlog.Int64("dropped_"+lvl.String(), n))
+		}
+	}
+	if total == 0 {
+		return
+	}
+	fields = append(fields, hlog.Int64("dropped", total))
+	s.logger.Warn("dropped log messages by sampling", fields...)
+}
+
+func (s *sampler) stop(ctx context.Context) error {
+	s.stopOnce.Do(func() { close(s.done) })
+	select {
+	case <-s.stopped:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+type samplingLogger struct {
+	hlog.Logger
+	s *sampler
+}
+
+func (l *samplingLogger) WithCtx(ctx context.Context, fields ...hlog.Field) hlog.Logger {
+	return newSamplingLogger(l.Logger.WithCtx(ctx, fields...), l.s)
+}
+
+func (l *samplingLogger) With(fields ...hlog.Field) hlog.Logger {
+	return newSamplingLogger(l.Logger.With(fields...), l.s)
+}
+
+func (l *samplingLogger) Named(name string) hlog.Logger {
+	return newSamplingLogger(hlog.NamedLogger(l.Logger, name), l.s)
+}
+
+// sample returns true if we should log the entry. It doesn't count
+// entries that the logger doesn't log at all, so they don't use the
+// budget and don't count as dropped.
+func (l *samplingLogger) sample(lvl hlog.Level, msg string) bool {
+	return l.Logger.Enabled(lvl) && l.s.sample(lvl, msg)
+}
+
+func (l *samplingLogger) Debug(msg string, fields ...hlog.Field) {
+	if l.sample(hlog.DebugLevel, msg) {
+		l.Logger.Debug(msg, fields...)
+	}
+}
+
+func (l *samplingLogger) Info(msg string, fields ...hlog.Field) {
+	if l.sample(hlog.InfoLevel, msg) {
+		l.Logger.Info(msg, fields...)
+	}
+}
+
+func (l *samplingLogger) Message(msg string, fields ...hlog.Field) {
+	if l.sample(hlog.InfoLevel, msg) {
+		l.Logger.Message(msg, fields...)
+	}
+}
+
+func (l *samplingLogger) Warn(msg string, fields ...hlog.Field) {
+	if l.sample(hlog.WarnLevel, msg) {
+		l.Logger.Warn(msg, fields...)
+	}
+}
+
+func (l *samplingLogger) Error(msg string, fields ...hlog.Field) {
+	if l.sample(hlog.ErrorLevel, msg) {
+		l.Logger.Error(msg, fields...)
+	}
+}
+
+// LoggerByName returns the logger with the name in the stack if the
+// logger is a stack of loggers, otherwise it returns nil.
+func (l *samplingLogger) LoggerByName(name string) hlog.Logger {
+	if s, ok := l.Logger.(hlog.Stack); ok {
+		return s.LoggerByName(name)
+	}
+	return nil
+}
+
+// LoggerNames returns names of the loggers in the stack if the logger is
+// a stack of loggers.
+func (l *samplingLogger) LoggerNames() []string {
+	if s, ok := l.Logger.(hlog.Stack); ok {
+		return s.LoggerNames()
+	}
+	return nil
+}
+
+func (l *samplingLogger) Boot() error {
+	if bootable, ok := l.Logger.(hexa.Bootable); ok {
+		return tracer.Trace(bootable.Boot())
+	}
+	return nil
+}
+
+// Shutdown logs the last summary and shuts down the logger.
+func (l *samplingLogger) Shutdown(ctx context.Context) error {
+	if err := l.s.stop(ctx); err != nil {
+		return tracer.Trace(err)
+	}
+	if s, ok := l.Logger.(hexa.Shutdownable); ok {
+		return tracer.Trace(s.Shutdown(ctx))
+	}
+	return nil
+}
+
+// leveledSamplingLogger is the sampling logger of loggers which can
+// change their level.
+type leveledSamplingLogger struct {
+	*samplingLogger
+}
+
+func (l *leveledSamplingLogger) Level() hlog.Level {
+	return l.Logger.(hlog.LevelSetter).Level()
+}
+
+func (l *leveledSamplingLogger) SetLevel(lvl hlog.Level) {
+	l.Logger.(hlog.LevelSetter).SetLevel(lvl)
+}
+
+func newSamplingLogger(l hlog.Logger, s *sampler) hlog.Logger {
+	sl := &samplingLogger{Logger: l, s: s}
+	if _, ok := l.(hlog.LevelSetter); ok {
+		return &leveledSamplingLogger{sl}
+	}
+	return sl
+}
+
+// NewSamplingDriver returns a logger which samples entries of the logger.
+// In each interval, it logs the first entries of each message per level
+// and then every Thereafter-th entry, and periodically logs the number of
+// dropped entries. Shut it down to stop its summaries.
+func NewSamplingDriver(l hlog.Logger, o SamplingOptions) hlog.Logger {
+	if o.Interval <= 0 {
+		o.Interval = time.Second
+	}
+	if o.Budget == (SamplingBudget{}) {
+		o.Budget = SamplingBudget{First: 100, Thereafter: 100}
+	}
+	if o.SummaryInterval <= 0 {
+		o.SummaryInterval = time.Minute
+	}
+	return newSamplingLogger(l, newSampler(l, o))
+}
+
+// Assert samplingLogger implements hexa Logger.
+var _ hlog.Logger = &samplingLogger{}
+var _ hlog.Namer = &samplingLogger{}
+var _ hlog.Stack = &samplingLogger{}
+var _ hexa.Bootable = &samplingLogger{}
+var _ hexa.Shutdownable = &samplingLogger{}
+var _ hlog.LevelSetter = &leveledSamplingLogger{}
@@ -0,0 +1,105 @@
+package logdriver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kamva/hexa"
+	"github.com/kamva/hexa/hlog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func newSampling(t *testing.T, o SamplingOptions) (hlog.Logger, *observer.ObservedLogs) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	l := NewSamplingDriver(NewZapDriver(zap.New(core)), o)
+	t.Cleanup(func() { _ = l.(hexa.Shutdownable).Shutdown(context.Background()) })
+	return l, logs
+}
+
+func TestSamplingLogger(t *testing.T) {
+	l, logs := newSampling(t, SamplingOptions{
+		Interval: time.Hour,
+		Budget:   SamplingBudget{First: 2, Thereafter: 3},
+		Levels:   map[hlog.Level]SamplingBudget{hlog.WarnLevel: {First: 1}},
+	})
+	child := l.With(hlog.String("a", "b"))
+
+	for i := 0; i < 8; i++ {
+		child.Info("a") // 1, 2, 5 and 8 pass.
+		l.Info("b")
+		l.Warn("c")
+		l.Error("d")
+	}
+	assert.Equal(t, 4, logs.FilterMessage("a").Len())
+	assert.Equal(t, 4, logs.FilterMessage("b").Len())
+	assert.Equal(t, 1, logs.FilterMessage("c").Len())
+	assert.Equal(t, 8, logs.FilterMessage("d").Len(), "errors are exempt")
+
+	require.NoError(t, l.(hexa.Shutdownable).Shutdown(context.Background()))
+	summary := logs.FilterMessage("dropped log messages by sampling").All()
+	require.Len(t, summary, 1)
+	assert.Equal(t, map[string]any{
+		"dropped_info": int64(8),
+		"dropped_warn": int64(7),
+		"dropped":      int64(15),
+	}, summary[0].ContextMap())
+}
+
+func TestSamplingLogger_Interval(t *testing.T) {
+	l, logs := newSampling(t, SamplingOptions{
+		Interval:        20 * time.Millisecond,
+		Budget:          SamplingBudget{First: 1},
+		SampleErrors:    true,
+		SummaryInterval: 10 * time.Millisecond,
+	})
+
+	l.Error("a")
+	l.Error("a")
+	assert.Equal(t, 1, logs.FilterMessage("a").Len())
+	time.Sleep(30 * time.Millisecond)
+	l.Error("a")
+	assert.Equal(t, 2, logs.FilterMessage("a").Len(), "the budget must reset each interval")
+
+	assert.Eventually(t, func() bool {
+		return logs.FilterMessage("dropped log messages by sampling").Len() == 1
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestSamplingLogger_LevelSetter(t *testing.T) {
+	l, _ := newSampling(t, SamplingOptions{})
+	require.Implements(t, (*hlog.LevelSetter)(nil), l)
+	l.(hlog.LevelSetter).SetLevel(hlog.WarnLevel)
+	assert.False(t, l.With().Enabled(hlog.InfoLevel))
+	assert.False(t, hlog.NamedLogger(l, "mongo").Enabled(hlog.InfoLevel))
+}
+
+func TestSamplingLogger_DisabledLevels(t *testing.T) {
+	l, logs := newSampling(t, SamplingOptions{Interval: time.Hour, Budget: SamplingBudget{First: 1}})
+	l.(hlog.LevelSetter).SetLevel(hlog.InfoLevel)
+
+	l.Debug("a") // disabled, so it doesn't use the budget.
+	l.Debug("a")
+	l.(hlog.LevelSetter).SetLevel(hlog.DebugLevel)
+	l.Debug("a")
+	assert.Equal(t, 1, logs.FilterMessage("a").Len())
+
+	require.NoError(t, l.(hexa.Shutdownable).Shutdown(context.Background()))
+	assert.Equal(t, 0, logs.FilterMessage("dropped log messages by sampling").Len())
+}
+
+func TestSamplingLogger_Stack(t *testing.T) {
+	zl := NewZapDriver(zap.New(zapcore.NewNopCore()))
+	l := NewSamplingDriver(NewStackLoggerDriverWith(hlog.InfoLevel, map[string]hlog.Logger{"zap": zl}), SamplingOptions{})
+	t.Cleanup(func() { _ = l.(hexa.Shutdownable).Shutdown(context.Background()) })
+
+	require.Implements(t, (*hlog.Stack)(nil), l)
+	require.Implements(t, (*hlog.LevelSetter)(nil), l)
+	require.Implements(t, (*hlog.Namer)(nil), l)
+	assert.Equal(t, []string{"zap"}, l.(hlog.Stack).LoggerNames())
+	assert.NotNil(t, l.(hlog.Stack).LoggerByName("zap"))
+}
@@ -79,6 +79,12 @@ func (l *sentryLogger) WithCtx(ctx context.Context, args ...hlog.Field) hlog.Log
 		l.setUser(scope, user, r)
 	}
 
+	// Set the span as tags, so we can search events of a trace.
+	for _, f := range hlog.TraceFields(ctx) {
+		key, val := hlog.FieldToKeyVal(f)
+		scope.SetTag(key, fmt.Sprint(val))
+	}
+
 	l.addFieldsToScope(scope, args)
 	return NewSentryDriverWith(hub)
 }
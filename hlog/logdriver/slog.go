@@ -0,0 +1,80 @@
+//go:build go1.21
+
+package logdriver
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/kamva/hexa/hlog"
+)
+
+type slogLogger struct {
+	handler slog.Handler
+	ctx     context.Context
+}
+
+func (l *slogLogger) Core() any {
+	return l.handler
+}
+
+func (l *slogLogger) Enabled(lvl hlog.Level) bool {
+	return l.handler.Enabled(l.ctx, hlog.SlogLevel(lvl))
+}
+
+// WithCtx keeps the context to pass it to the handler.
+func (l *slogLogger) WithCtx(ctx context.Context, fields ...hlog.Field) hlog.Logger {
+	h := l.handler
+	if len(fields) > 0 {
+		h = h.WithAttrs(hlog.FieldsToAttrs(fields...))
+	}
+	return &slogLogger{handler: h, ctx: ctx}
+}
+
+func (l *slogLogger) With(fields ...hlog.Field) hlog.Logger {
+	if len(fields) > 0 {
+		return &slogLogger{handler: l.handler.WithAttrs(hlog.FieldsToAttrs(fields...)), ctx: l.ctx}
+	}
+	return l
+}
+
+func (l *slogLogger) log(lvl hlog.Level, msg string, fields ...hlog.Field) {
+	level := hlog.SlogLevel(lvl)
+	if !l.handler.Enabled(l.ctx, level) {
+		return
+	}
+
+	r := slog.NewRecord(time.Now(), level, msg, 0)
+	r.AddAttrs(hlog.FieldsToAttrs(fields...)...)
+	_ = l.handler.Handle(l.ctx, r) // like zap, we ignore errors of writing logs.
+}
+
+func (l *slogLogger) Debug(msg string, fields ...hlog.Field) {
+	l.log(hlog.DebugLevel, msg, fields...)
+}
+
+func (l *slogLogger) Info(msg string, fields ...hlog.Field) {
+	l.log(hlog.InfoLevel, msg, fields...)
+}
+
+func (l *slogLogger) Message(msg string, fields ...hlog.Field) {
+	l.log(hlog.InfoLevel, msg, fields...)
+}
+
+func (l *slogLogger) Warn(msg string, fields ...hlog.Field) {
+	l.log(hlog.WarnLevel, msg, fields...)
+}
+
+func (l *slogLogger) Error(msg string, fields ...hlog.Field) {
+	l.log(hlog.ErrorLevel, msg, fields...)
+}
+
+// NewSlogDriver returns new instance of hexa logger which writes to the
+// slog handler, e.g., NewSlogDriver(slog.Default().Handler()).
+func NewSlogDriver(h slog.Handler) hlog.Logger {
+	return &slogLogger{handler: h, ctx: context.Background()}
+}
+
+// Assert slogLogger implements hexa Logger.
+var _ hlog.Logger = &slogLogger{}
@@ -0,0 +1,110 @@
+//go:build go1.21
+
+package logdriver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/kamva/hexa/hlog"
+	"github.com/kamva/tracer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestSlogHandler(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	l := slog.New(hlog.NewSlogHandler(NewZapDriver(zap.New(core))))
+
+	l.Debug("hidden")
+	l.With("app", "hexa").WithGroup("req").With("id", 7).Warn("slow request",
+		slog.Duration("took", time.Second),
+		slog.Group("user", slog.String("name", "mehran"), slog.Bool("admin", true)),
+		slog.Any("err", errors.New("timeout")),
+	)
+	l.WithGroup("empty").Info("no attrs")
+
+	entries := logs.AllUntimed()
+	require.Len(t, entries, 2)
+	assert.Equal(t, zapcore.WarnLevel, entries[0].Level)
+	assert.Equal(t, "slow request", entries[0].Message)
+	assert.Equal(t, map[string]any{
+		"app": "hexa",
+		"req": map[string]any{
+			"id":   int64(7),
+			"took": time.Second,
+			"user": map[string]any{"name": "mehran", "admin": true},
+			"err":  "timeout",
+		},
+	}, entries[0].ContextMap())
+	assert.Empty(t, entries[1].ContextMap(), "empty groups must be omitted")
+}
+
+func TestSlogDriver(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewSlogDriver(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo}))
+
+	assert.False(t, l.Enabled(hlog.DebugLevel))
+	assert.True(t, l.Enabled(hlog.WarnLevel))
+	l.Debug("hidden")
+
+	err := tracer.Trace(errors.New("boom"))
+	l.With(hlog.String("app", "hexa")).WithCtx(context.Background(), hlog.Int("n", 3)).Error("failed",
+		hlog.Err(err),
+		hlog.ErrStack(err),
+		hlog.Group("db", hlog.String("name", "users"), hlog.Duration("took", time.Millisecond)),
+		zap.Namespace("extra"),
+		hlog.Bool("retry", false),
+	)
+
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, "ERROR", entry["level"])
+	assert.Equal(t, "failed", entry["msg"])
+	assert.Equal(t, "hexa", entry["app"])
+	assert.Equal(t, float64(3), entry["n"])
+	assert.Equal(t, "boom", entry["error"])
+	assert.NotEmpty(t, entry[hlog.ErrorStackLogKey])
+	assert.Equal(t, map[string]any{"name": "users", "took": float64(time.Millisecond)}, entry["db"])
+	assert.Equal(t, map[string]any{"retry": false}, entry["extra"])
+}
+
+func TestSlog_RoundTrip(t *testing.T) {
+	now := time.Now()
+	err := errors.New("boom")
+	fields := []hlog.Field{
+		hlog.String("s", "v"),
+		hlog.Int64("i", -1),
+		hlog.Uint64("u", 1),
+		zap.Float64("f", 1.5),
+		hlog.Bool("b", true),
+		hlog.Duration("d", time.Second),
+		hlog.Time("t", now),
+		hlog.NamedErr("e", err),
+		hlog.Group("g", hlog.String("k", "v"), hlog.Group("inner", hlog.Int("n", 1))),
+	}
+
+	got := hlog.AttrsToFields(hlog.FieldsToAttrs(fields...)...)
+	require.Len(t, got, len(fields))
+	for i := range fields {
+		wk, wv := hlog.FieldToKeyVal(fields[i])
+		gk, gv := hlog.FieldToKeyVal(got[i])
+		assert.Equal(t, wk, gk)
+		assert.Equal(t, wv, gv, wk)
+	}
+}
+
+func TestSlogLevels(t *testing.T) {
+	for _, l := range []hlog.Level{hlog.DebugLevel, hlog.InfoLevel, hlog.WarnLevel, hlog.ErrorLevel} {
+		assert.Equal(t, l, hlog.LevelFromSlog(hlog.SlogLevel(l)))
+	}
+	assert.Equal(t, hlog.InfoLevel, hlog.LevelFromSlog(slog.LevelInfo+2))
+}
@@ -3,6 +3,7 @@ package logdriver
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/kamva/hexa"
@@ -22,75 +23,160 @@ type StackedLogger interface {
 	// logger can be nil if does not exists.
 	LoggerByName(name string) hlog.Logger
 
+	// LoggerNames returns names of the loggers in the stack.
+	LoggerNames() []string
+
 	hexa.Bootable
 	hexa.Shutdownable
 }
 
 type stackedLogger struct {
-	lvl   hlog.Level
+	lvl   *hlog.AtomicLevel
 	stack map[string]hlog.Logger
+	name  string
+	// redactor redacts fields once before passing them to the stack.
+	redactor *hlog.Redactor
 }
 
 func (l *stackedLogger) LoggerByName(name string) hlog.Logger {
 	return l.stack[name]
 }
 
+func (l *stackedLogger) LoggerNames() []string {
+	names := make([]string, 0, len(l.stack))
+	for name := range l.stack {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 func (l *stackedLogger) Core() any {
 	return l.stack
 }
 
+// Enabled returns true if the stack's level covers the level or any
+// logger in the stack is enabled for it (e.g., we changed its level).
 func (l *stackedLogger) Enabled(lvl hlog.Level) bool {
-	return l.lvl.CanLog(lvl)
+	if l.Level().CanLog(lvl) {
+		return true
+	}
+	for _, logger := range l.stack {
+		if logger.Enabled(lvl) {
+			return true
+		}
+	}
+	return false
+}
+
+func (l *stackedLogger) Level() hlog.Level {
+	return hlog.EffectiveLevel(l.name, l.lvl.Level())
+}
+
+// SetLevel changes level of the stack and all loggers in the stack
+// which can change their level. If it's a named logger, it sets level
+// of its name.
+func (l *stackedLogger) SetLevel(lvl hlog.Level) {
+	if l.name != "" {
+		hlog.SetNamedLevel(l.name, lvl)
+		return
+	}
+	l.lvl.SetLevel(lvl)
+	for _, logger := range l.stack {
+		if s, ok := logger.(hlog.LevelSetter); ok {
+			s.SetLevel(lvl)
+		}
+	}
 }
 
 func (l *stackedLogger) WithCtx(ctx context.Context, fields ...hlog.Field) hlog.Logger {
+	fields = l.redact(fields)
 	stack := make(map[string]hlog.Logger)
 	for k, logger := range l.stack {
 		stack[k] = logger.WithCtx(ctx, fields...)
 	}
 
-	return NewStackLoggerDriverWith(l.lvl, stack)
+	return &stackedLogger{lvl: l.lvl, stack: stack, name: l.name, redactor: l.redactor}
+}
+
+// Named returns the stack of named loggers, loggers which don't support
+// names log the name as a field.
+func (l *stackedLogger) Named(name string) hlog.Logger {
+	stack := make(map[string]hlog.Logger)
+	for k, logger := range l.stack {
+		stack[k] = hlog.NamedLogger(logger, name)
+	}
+
+	return &stackedLogger{lvl: l.lvl, stack: stack, name: hlog.JoinNames(l.name, name), redactor: l.redactor}
 }
 
 func (l *stackedLogger) With(fields ...hlog.Field) hlog.Logger {
+	fields = l.redact(fields)
 	stack := make(map[string]hlog.Logger)
 	for k, logger := range l.stack {
 		stack[k] = logger.With(fields...)
 	}
 
-	return NewStackLoggerDriverWith(l.lvl, stack)
+	return &stackedLogger{lvl: l.lvl, stack: stack, name: l.name, redactor: l.redactor}
 }
 
 func (l *stackedLogger) Debug(msg string, fields ...hlog.Field) {
+	if !l.Enabled(hlog.DebugLevel) {
+		return
+	}
+	fields = l.redact(fields)
 	for _, logger := range l.stack {
 		logger.Debug(msg, fields...)
 	}
 }
 
 func (l *stackedLogger) Info(msg string, fields ...hlog.Field) {
+	if !l.Enabled(hlog.InfoLevel) {
+		return
+	}
+	fields = l.redact(fields)
 	for _, logger := range l.stack {
 		logger.Info(msg, fields...)
 	}
 }
 
 func (l *stackedLogger) Message(msg string, fields ...hlog.Field) {
+	if !l.Enabled(hlog.InfoLevel) {
+		return
+	}
+	fields = l.redact(fields)
 	for _, logger := range l.stack {
 		logger.Message(msg, fields...)
 	}
 }
 
 func (l *stackedLogger) Warn(msg string, fields ...hlog.Field) {
+	if !l.Enabled(hlog.WarnLevel) {
+		return
+	}
+	fields = l.redact(fields)
 	for _, logger := range l.stack {
 		logger.Warn(msg, fields...)
 	}
 }
 
 func (l *stackedLogger) Error(msg string, fields ...hlog.Field) {
+	if !l.Enabled(hlog.ErrorLevel) {
+		return
+	}
+	fields = l.redact(fields)
 	for _, logger := range l.stack {
 		logger.Error(msg, fields...)
 	}
 }
 
+func (l *stackedLogger) redact(fields []hlog.Field) []hlog.Field {
+	if l.redactor == nil {
+		return fields
+	}
+	return l.redactor.Redact(fields)
+}
+
 func (l *stackedLogger) Boot() error {
 	for _, logger := range l.stack {
 		if bootable, ok := logger.(hexa.Bootable); ok {
@@ -124,6 +210,10 @@ type StackOptions struct {
 	Level      hlog.Level
 	ZapConfig  zap.Config
 	SentryOpts *SentryOptions
+	// Redact redacts sensitive values of the fields once before passing
+	// them to the loggers, e.g., &hlog.RedactOptions{} redacts using the
+	// default patterns. nil value disables it.
+	Redact *hlog.RedactOptions
 }
 
 // NewStackLoggerDriver return new stacked logger .
@@ -151,14 +241,21 @@ func NewStackLoggerDriver(stackList []string, opts StackOptions) (hlog.Logger, e
 		}
 	}
 
-	return NewStackLoggerDriverWith(opts.Level, stack), nil
+	l := &stackedLogger{lvl: hlog.NewAtomicLevel(opts.Level), stack: stack}
+	if opts.Redact != nil {
+		l.redactor = hlog.NewRedactor(*opts.Redact)
+	}
+	return l, nil
 }
 
 // NewStackLoggerDriverWith return new instance of hexa logger with stacked logger driver.
 func NewStackLoggerDriverWith(lvl hlog.Level, stack map[string]hlog.Logger) hlog.Logger {
-	return &stackedLogger{lvl: lvl, stack: stack}
+	return &stackedLogger{lvl: hlog.NewAtomicLevel(lvl), stack: stack}
 }
 
 // Assert stackedLogger implements hexa Logger.
 var _ hlog.Logger = &stackedLogger{}
 var _ StackedLogger = &stackedLogger{}
+var _ hlog.LevelSetter = &stackedLogger{}
+var _ hlog.Stack = &stackedLogger{}
+var _ hlog.Namer = &stackedLogger{}
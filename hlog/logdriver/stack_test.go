@@ -5,7 +5,10 @@ import (
 
 	"github.com/kamva/hexa/hlog"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
 )
 
 func TestStackedLogger_LoggerByName(t *testing.T) {
@@ -22,3 +25,91 @@ func TestStackedLogger_LoggerByName(t *testing.T) {
 		assert.NotNil(t, sl.LoggerByName(ZapLogger))
 	}
 }
+
+func TestStackedLogger_SetLevel(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	zl := NewZapDriver(zap.New(core))
+	l := NewStackLoggerDriverWith(hlog.InfoLevel, map[string]hlog.Logger{
+		ZapLogger:     zl,
+		PrinterLogger: hlog.NewPrinterDriver(hlog.InfoLevel),
+	})
+	child := l.With(hlog.String("a", "b"))
+	sl := l.(StackedLogger)
+	assert.Equal(t, []string{PrinterLogger, ZapLogger}, sl.LoggerNames())
+
+	l.(hlog.LevelSetter).SetLevel(hlog.ErrorLevel)
+	assert.False(t, child.Enabled(hlog.WarnLevel))
+	assert.Equal(t, hlog.ErrorLevel, zl.(hlog.LevelSetter).Level())
+	child.Warn("hidden")
+	assert.Zero(t, logs.Len())
+
+	// Changing level of a logger in the stack enables the stack for it.
+	zl.(hlog.LevelSetter).SetLevel(hlog.WarnLevel)
+	assert.True(t, child.Enabled(hlog.WarnLevel))
+	child.Warn("shown")
+	assert.Equal(t, 1, logs.Len())
+}
+
+func TestZapLogger_SetLevel(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	l := NewZapDriver(zap.New(core))
+	child := l.With(hlog.String("a", "b"))
+	s := l.(hlog.LevelSetter)
+	assert.Equal(t, hlog.InfoLevel, s.Level())
+
+	s.SetLevel(hlog.WarnLevel)
+	assert.False(t, child.Enabled(hlog.InfoLevel))
+	child.Info("hidden")
+	child.Warn("shown")
+	require.Equal(t, 1, logs.Len())
+	assert.Equal(t, "shown", logs.All()[0].Message)
+
+	// The core's level is still the floor.
+	s.SetLevel(hlog.DebugLevel)
+	assert.False(t, child.Enabled(hlog.DebugLevel))
+
+	cfg := DefaultZapConfig(false, zapcore.InfoLevel, "json")
+	cl := NewZapDriverFromConfig(cfg)
+	cl.(hlog.LevelSetter).SetLevel(hlog.DebugLevel)
+	assert.True(t, cl.Enabled(hlog.DebugLevel))
+}
+
+func TestZapLogger_Named(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	l := NewZapDriverWithLevel(zap.New(core), zap.NewAtomicLevelAt(zapcore.InfoLevel))
+	mongo := hlog.NamedLogger(l, "mongo").With(hlog.String("a", "b"))
+	commands := hlog.NamedLogger(mongo, "commands")
+
+	hlog.SetNamedLevel("mongo.commands", hlog.DebugLevel)
+	defer hlog.UnsetNamedLevel("mongo.commands")
+
+	l.Debug("hidden")
+	mongo.Debug("hidden")
+	commands.Debug("shown")
+	require.Equal(t, 1, logs.Len())
+	assert.Equal(t, map[string]any{"a": "b", hlog.LoggerNameKey: "mongo.commands"}, logs.All()[0].ContextMap())
+
+	mongo.Info("info")
+	assert.Equal(t, "mongo", logs.All()[1].ContextMap()[hlog.LoggerNameKey])
+}
+
+func TestStackedLogger_Named(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	l := NewStackLoggerDriverWith(hlog.InfoLevel, map[string]hlog.Logger{
+		ZapLogger:     NewZapDriverWithLevel(zap.New(core), zap.NewAtomicLevelAt(zapcore.InfoLevel)),
+		PrinterLogger: hlog.NewPrinterDriver(hlog.InfoLevel),
+	})
+	mongo := hlog.NamedLogger(l, "mongo")
+	assert.False(t, mongo.Enabled(hlog.DebugLevel))
+
+	mongo.(hlog.LevelSetter).SetLevel(hlog.DebugLevel)
+	defer hlog.UnsetNamedLevel("mongo")
+	assert.Equal(t, hlog.DebugLevel, hlog.NamedLevels()["mongo"])
+	assert.True(t, mongo.Enabled(hlog.DebugLevel))
+	assert.False(t, l.Enabled(hlog.DebugLevel))
+
+	mongo.Debug("shown")
+	l.Debug("hidden")
+	require.Equal(t, 1, logs.Len())
+	assert.Equal(t, "mongo", logs.All()[0].ContextMap()[hlog.LoggerNameKey])
+}
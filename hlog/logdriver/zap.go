@@ -10,6 +10,11 @@ import (
 
 type zapLogger struct {
 	logger *zap.Logger
+	level  zap.AtomicLevel
+	name   string
+	// trace is the span fields of the logger, we keep them out of the
+	// zap logger, so WithCtx can replace them.
+	trace []hlog.Field
 }
 
 func (l *zapLogger) Core() any {
@@ -20,35 +25,83 @@ func (l *zapLogger) Enabled(lvl hlog.Level) bool {
 	return l.logger.Core().Enabled(hlog.ZapLevel(lvl))
 }
 
-func (l *zapLogger) WithCtx(_ context.Context, fields ...hlog.Field) hlog.Logger {
-	return l.With(fields...)
+func (l *zapLogger) Level() hlog.Level {
+	return hlog.EffectiveLevel(l.name, hlog.LevelFromZap(l.level.Level()))
+}
+
+// SetLevel sets level of the logger, or level of its name if it's a
+// named logger.
+func (l *zapLogger) SetLevel(lvl hlog.Level) {
+	if l.name != "" {
+		hlog.SetNamedLevel(l.name, lvl)
+		return
+	}
+	l.level.SetLevel(hlog.ZapLevel(lvl))
+}
+
+func (l *zapLogger) Named(name string) hlog.Logger {
+	name = hlog.JoinNames(l.name, name)
+	enabler := &namedLevel{level: l.level, name: name}
+	logger := l.logger.WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		if lc, ok := core.(*levelCore); ok {
+			core = lc.Core
+		}
+		return &levelCore{Core: core, level: enabler}
+	}))
+	return &zapLogger{logger: logger, level: l.level, name: name, trace: l.trace}
+}
+
+// entryFields adds name of the logger and its span fields to the fields.
+func (l *zapLogger) entryFields(fields []hlog.Field) []hlog.Field {
+	if l.name == "" && len(l.trace) == 0 {
+		return fields
+	}
+	fields = append(fields[:len(fields):len(fields)], l.trace...)
+	if l.name != "" {
+		fields = append(fields, hlog.String(hlog.LoggerNameKey, l.name))
+	}
+	return fields
+}
+
+// WithCtx adds the fields to the logger, and replaces its span fields by
+// the context's span fields.
+func (l *zapLogger) WithCtx(ctx context.Context, fields ...hlog.Field) hlog.Logger {
+	logger := l.logger
+	if len(fields) > 0 {
+		logger = logger.With(fields...)
+	}
+	trace := l.trace
+	if t := hlog.TraceFields(ctx); t != nil {
+		trace = t
+	}
+	return &zapLogger{logger: logger, level: l.level, name: l.name, trace: trace}
 }
 
 func (l *zapLogger) With(fields ...hlog.Field) hlog.Logger {
 	if len(fields) > 0 {
-		return NewZapDriver(l.logger.With(fields...))
+		return &zapLogger{logger: l.logger.With(fields...), level: l.level, name: l.name, trace: l.trace}
 	}
 	return l
 }
 
 func (l *zapLogger) Debug(msg string, fields ...hlog.Field) {
-	l.logger.Debug(msg, fields...)
+	l.logger.Debug(msg, l.entryFields(fields)...)
 }
 
 func (l *zapLogger) Info(msg string, fields ...hlog.Field) {
-	l.logger.Info(msg, fields...)
+	l.logger.Info(msg, l.entryFields(fields)...)
 }
 
 func (l *zapLogger) Message(msg string, fields ...hlog.Field) {
-	l.logger.Info(msg, fields...)
+	l.logger.Info(msg, l.entryFields(fields)...)
 }
 
 func (l *zapLogger) Warn(msg string, fields ...hlog.Field) {
-	l.logger.Warn(msg, fields...)
+	l.logger.Warn(msg, l.entryFields(fields)...)
 }
 
 func (l *zapLogger) Error(msg string, fields ...hlog.Field) {
-	l.logger.Error(msg, fields...)
+	l.logger.Error(msg, l.entryFields(fields)...)
 }
 
 type ZapOptions struct {
@@ -75,18 +128,81 @@ func DefaultZapConfig(debug bool, level zapcore.Level, encoding string) zap.Conf
 	return cfg
 }
 
+// NewZapDriverFromConfig builds the logger from the config. Its level is
+// the config's level, and named loggers can log lower levels than it.
 func NewZapDriverFromConfig(cfg zap.Config) hlog.Logger {
+	lvl := cfg.Level
+	cfg.Level = zap.NewAtomicLevelAt(zapcore.DebugLevel) // we check the level in the levelCore.
 	l, err := cfg.Build()
 	if err != nil {
 		panic(err)
 	}
-	return NewZapDriver(l)
+	return NewZapDriverWithLevel(l, lvl)
 }
 
 // NewZapDriver return new instance of hexa logger with zap driver.
+// Its level can change at runtime, but the logger still doesn't log
+// levels that its core doesn't enable, use NewZapDriverFromConfig to
+// control the core's level too.
 func NewZapDriver(logger *zap.Logger) hlog.Logger {
-	return &zapLogger{logger}
+	return NewZapDriverWithLevel(logger, zap.NewAtomicLevelAt(lowestLevel(logger.Core())))
+}
+
+// NewZapDriverWithLevel return new instance of hexa logger with zap driver
+// whose level is the provided level.
+func NewZapDriverWithLevel(logger *zap.Logger, lvl zap.AtomicLevel) hlog.Logger {
+	logger = logger.WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return &levelCore{Core: core, level: lvl}
+	}))
+	return &zapLogger{logger: logger, level: lvl}
+}
+
+// levelCore checks its level in addition to its core's level.
+type levelCore struct {
+	zapcore.Core
+	level zapcore.LevelEnabler
+}
+
+func (c *levelCore) Enabled(lvl zapcore.Level) bool {
+	return c.level.Enabled(lvl) && c.Core.Enabled(lvl)
+}
+
+func (c *levelCore) With(fields []zapcore.Field) zapcore.Core {
+	return &levelCore{Core: c.Core.With(fields), level: c.level}
+}
+
+func (c *levelCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.level.Enabled(ent.Level) {
+		return ce
+	}
+	return c.Core.Check(ent, ce)
+}
+
+// namedLevel enables levels of a named logger, its level is the level
+// of its name or the logger's level.
+type namedLevel struct {
+	level zap.AtomicLevel
+	name  string
+}
+
+func (l *namedLevel) Enabled(lvl zapcore.Level) bool {
+	if named, ok := hlog.NamedLevel(l.name); ok {
+		return hlog.ZapLevel(named).Enabled(lvl)
+	}
+	return l.level.Enabled(lvl)
+}
+
+// lowestLevel returns the lowest level that the core enables.
+func lowestLevel(core zapcore.Core) zapcore.Level {
+	for lvl := zapcore.DebugLevel; lvl < zapcore.FatalLevel; lvl++ {
+		if core.Enabled(lvl) {
+			return lvl
+		}
+	}
+	return zapcore.FatalLevel
 }
 
 // Assert zapLogger implements hexa Logger.
 var _ hlog.Logger = &zapLogger{}
+var _ hlog.LevelSetter = &zapLogger{}
+var _ hlog.Namer = &zapLogger{}
@@ -0,0 +1,52 @@
+package logdriver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kamva/hexa/hlog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/sdk/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestZapLogger_WithCtx(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	l := NewZapDriver(zap.New(core))
+
+	l.WithCtx(context.Background(), hlog.String("a", "b")).Info("no span")
+	ctx, span := trace.NewTracerProvider().Tracer("test").Start(context.Background(), "op")
+	defer span.End()
+	l.WithCtx(ctx).Info("span")
+
+	entries := logs.All()
+	require.Len(t, entries, 2)
+	assert.Equal(t, map[string]any{"a": "b"}, entries[0].ContextMap())
+	assert.Equal(t, map[string]any{
+		hlog.TraceIDKey:      span.SpanContext().TraceID().String(),
+		hlog.SpanIDKey:       span.SpanContext().SpanID().String(),
+		hlog.TraceSampledKey: true,
+	}, entries[1].ContextMap())
+}
+
+func TestZapLogger_WithCtxReplacesSpan(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	tr := trace.NewTracerProvider().Tracer("test")
+	parent, ps := tr.Start(context.Background(), "parent")
+	defer ps.End()
+	child, cs := tr.Start(parent, "child")
+	defer cs.End()
+
+	l := hlog.NamedLogger(NewZapDriver(zap.New(core)).WithCtx(parent, hlog.String("a", "b")), "db")
+	l.WithCtx(child).Info("child")
+	l.WithCtx(context.Background()).Info("no span")
+
+	entries := logs.All()
+	require.Len(t, entries, 2)
+	assert.Len(t, entries[0].Context, 5, "span fields must not repeat")
+	assert.Equal(t, cs.SpanContext().SpanID().String(), entries[0].ContextMap()[hlog.SpanIDKey])
+	assert.Equal(t, ps.SpanContext().SpanID().String(), entries[1].ContextMap()[hlog.SpanIDKey], "contexts without span keep the span")
+}
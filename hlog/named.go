@@ -0,0 +1,120 @@
+package hlog
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// LoggerNameKey is the key of the field which contains name of a named
+// logger.
+const LoggerNameKey = "logger"
+
+// Namer is implemented by loggers which support named loggers. The level
+// of a named logger is the level that we set for its name or prefix using
+// SetNamedLevel, otherwise it's the level of its parent.
+type Namer interface {
+	// Named returns a child logger with the name. Naming a named logger
+	// joins the names by a dot, e.g., "mongo.commands".
+	Named(name string) Logger
+}
+
+// NamedLogger returns a named child of the logger. If the logger doesn't
+// implement Namer, the child just logs the name as a field.
+func NamedLogger(l Logger, name string) Logger {
+	if n, ok := l.(Namer); ok {
+		return n.Named(name)
+	}
+	return l.With(String(LoggerNameKey, name))
+}
+
+// Named returns a named child of the global logger, e.g., Named("mongo").
+func Named(name string) Logger {
+	return NamedLogger(GlobalLogger(), name)
+}
+
+// JoinNames joins name of a named logger and its child.
+func JoinNames(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+	return parent + "." + name
+}
+
+// namedLevels keeps a snapshot of the named levels, so loggers read them
+// without locking.
+var namedLevels = struct {
+	mu       sync.Mutex
+	snapshot atomic.Value // map[string]Level
+}{}
+
+func init() {
+	namedLevels.snapshot.Store(map[string]Level{})
+}
+
+// SetNamedLevel sets level of the named loggers with the name. If the name
+// ends with "*", it's a prefix and sets level of all loggers whose names
+// start with it, e.g., "mongo*". The exact name wins over prefixes, and the
+// longest prefix wins over other prefixes.
+func SetNamedLevel(name string, lvl Level) {
+	updateNamedLevels(func(m map[string]Level) { m[name] = lvl })
+}
+
+// UnsetNamedLevel removes the level of the name or prefix, so the named
+// loggers use their parent's level.
+func UnsetNamedLevel(name string) {
+	updateNamedLevels(func(m map[string]Level) { delete(m, name) })
+}
+
+// NamedLevels returns levels that we set for names and prefixes.
+func NamedLevels() map[string]Level {
+	m := namedLevels.snapshot.Load().(map[string]Level)
+	levels := make(map[string]Level, len(m))
+	for k, v := range m {
+		levels[k] = v
+	}
+	return levels
+}
+
+// NamedLevel returns the level that we set for the named logger's name or
+// its prefixes.
+func NamedLevel(name string) (Level, bool) {
+	m := namedLevels.snapshot.Load().(map[string]Level)
+	if lvl, ok := m[name]; ok {
+		return lvl, true
+	}
+
+	var lvl Level
+	prefixLen := -1
+	for k, v := range m {
+		if !strings.HasSuffix(k, "*") {
+			continue
+		}
+		prefix := strings.TrimSuffix(k, "*")
+		if len(prefix) > prefixLen && strings.HasPrefix(name, prefix) {
+			lvl, prefixLen = v, len(prefix)
+		}
+	}
+	return lvl, prefixLen != -1
+}
+
+// EffectiveLevel returns level of the named logger with the name, or the
+// parent's level if we didn't set a level for it.
+func EffectiveLevel(name string, parent Level) Level {
+	if name == "" {
+		return parent
+	}
+	if lvl, ok := NamedLevel(name); ok {
+		return lvl
+	}
+	return parent
+}
+
+func updateNamedLevels(fn func(m map[string]Level)) {
+	namedLevels.mu.Lock()
+	defer namedLevels.mu.Unlock()
+
+	m := NamedLevels()
+	fn(m)
+	namedLevels.snapshot.Store(m)
+}
@@ -0,0 +1,57 @@
+package hlog
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// setNamedLevel sets the named level for the test.
+func setNamedLevel(t *testing.T, name string, lvl Level) {
+	SetNamedLevel(name, lvl)
+	t.Cleanup(func() { UnsetNamedLevel(name) })
+}
+
+func TestNamedLevel(t *testing.T) {
+	setNamedLevel(t, "mongo", DebugLevel)
+	setNamedLevel(t, "mongo*", WarnLevel)
+	setNamedLevel(t, "mongo.commands*", ErrorLevel)
+
+	cases := []struct {
+		name string
+		lvl  Level
+		ok   bool
+	}{
+		{"mongo", DebugLevel, true},          // exact name wins.
+		{"mongo.pool", WarnLevel, true},      // prefix.
+		{"mongo.commands", ErrorLevel, true}, // the longest prefix wins.
+		{"redis", 0, false},
+	}
+	for _, c := range cases {
+		lvl, ok := NamedLevel(c.name)
+		assert.Equal(t, c.ok, ok, c.name)
+		assert.Equal(t, c.lvl, lvl, c.name)
+	}
+
+	assert.Equal(t, InfoLevel, EffectiveLevel("redis", InfoLevel))
+	assert.Equal(t, InfoLevel, EffectiveLevel("", InfoLevel))
+	assert.Len(t, NamedLevels(), 3)
+}
+
+func TestPrinterLogger_Named(t *testing.T) {
+	l := NewPrinterDriver(InfoLevel)
+	mongo := l.(Namer).Named("mongo")
+	commands := mongo.(Namer).Named("commands")
+	assert.Equal(t, "mongo.commands", commands.(*printerLogger).name)
+
+	setNamedLevel(t, "mongo*", DebugLevel)
+	assert.True(t, commands.Enabled(DebugLevel))
+	assert.True(t, mongo.With(String("a", "b")).Enabled(DebugLevel))
+	assert.False(t, l.Enabled(DebugLevel))
+
+	// Setting level of a named logger sets level of its name.
+	commands.(LevelSetter).SetLevel(ErrorLevel)
+	t.Cleanup(func() { UnsetNamedLevel("mongo.commands") })
+	assert.False(t, commands.Enabled(WarnLevel))
+	assert.Equal(t, InfoLevel, l.(LevelSetter).Level())
+}
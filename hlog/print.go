@@ -8,15 +8,34 @@ import (
 
 type printerLogger struct {
 	timeFormat string
-	level      Level
+	level      *AtomicLevel
 	with       []Field
+	name       string
 }
 
 func (l *printerLogger) Core() any {
 	return fmt.Println
 }
 func (l *printerLogger) Enabled(lvl Level) bool {
-	return l.level.CanLog(lvl)
+	return l.Level().CanLog(lvl)
+}
+func (l *printerLogger) Level() Level {
+	return EffectiveLevel(l.name, l.level.Level())
+}
+
+// SetLevel sets level of the logger, or level of its name if it's a
+// named logger.
+func (l *printerLogger) SetLevel(lvl Level) {
+	if l.name != "" {
+		SetNamedLevel(l.name, lvl)
+		return
+	}
+	l.level.SetLevel(lvl)
+}
+func (l *printerLogger) Named(name string) Logger {
+	clone := l.clone()
+	clone.name = JoinNames(l.name, name)
+	return clone
 }
 func (l *printerLogger) cloneData() []Field {
 	dst := make([]Field, len(l.with))
@@ -28,23 +47,29 @@ func (l *printerLogger) clone() *printerLogger {
 		timeFormat: l.timeFormat,
 		level:      l.level,
 		with:       l.cloneData(),
+		name:       l.name,
 	}
 }
-func (l *printerLogger) WithCtx(_ context.Context, fields ...Field) Logger {
+func (l *printerLogger) WithCtx(ctx context.Context, fields ...Field) Logger {
 	clone := l.clone()
-	clone.with = append(clone.with, fields...)
+	clone.with = append(ReplaceTraceFields(ctx, clone.with), fields...)
 	return clone
 }
 
 func (l *printerLogger) With(fields ...Field) Logger {
-	return l.WithCtx(context.Background(), fields...)
+	clone := l.clone()
+	clone.with = append(clone.with, fields...)
+	return clone
 }
 
 func (l *printerLogger) log(level Level, msg string, fields ...Field) {
 	ll := l.With(fields...).(*printerLogger)
+	if l.name != "" {
+		ll.with = append(ll.with, String(LoggerNameKey, l.name))
+	}
 	t := time.Now().Format(l.timeFormat)
 
-	if l.level.CanLog(level) {
+	if l.Enabled(level) {
 		fmt.Println(fmt.Sprintf("%s %s: ", t, level), fieldsToMap(ll.with...), msg)
 	}
 }
@@ -76,10 +101,12 @@ func (l *printerLogger) Error(msg string, fields ...Field) {
 func NewPrinterDriver(l Level) Logger {
 	return &printerLogger{
 		timeFormat: "2006-01-02T15:04:05.000-0700",
-		level:      l,
+		level:      NewAtomicLevel(l),
 		with:       make([]Field, 0),
 	}
 }
 
 // Assert printerLogger implements hexa Logger.
 var _ Logger = &printerLogger{}
+var _ LevelSetter = &printerLogger{}
+var _ Namer = &printerLogger{}
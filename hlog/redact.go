@@ -0,0 +1,260 @@
+package hlog
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// Redacted is the default replacement of sensitive values.
+const Redacted = "REDACTED"
+
+// maxRedactDepth is the maximum depth of values which we walk, we replace
+// deeper values.
+const maxRedactDepth = 16
+
+var (
+	// DefaultRedactKeys are patterns of the keys whose values are sensitive.
+	DefaultRedactKeys = []*regexp.Regexp{
+		regexp.MustCompile(`(?i)passw(or)?d|passphrase|^pass$`),
+		regexp.MustCompile(`(?i)secret|token|authorization|api[_-]?key|cookie`),
+		regexp.MustCompile(`(?i)card[_-]?(number|num|no)|credit[_-]?card|cvv|cvc|^pan$`),
+	}
+
+	// DefaultRedactValues detect sensitive parts of values.
+	DefaultRedactValues = []*regexp.Regexp{
+		regexp.MustCompile(`eyJ[A-Za-z0-9_-]*\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`), // JWT
+		regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`),    // email
+	}
+)
+
+type RedactOptions struct {
+	// Keys are patterns of the keys whose values we replace, default value
+	// is DefaultRedactKeys. Set it to an empty slice to disable it.
+	Keys []*regexp.Regexp
+
+	// Values are patterns of the sensitive parts of string values which we
+	// replace, default value is DefaultRedactValues. Set it to an empty slice
+	// to disable it.
+	Values []*regexp.Regexp
+
+	// Replacement replaces the sensitive values, default value is Redacted.
+	Replacement string
+}
+
+// Redactor redacts sensitive values of fields. It checks keys of the fields
+// and keys of maps and structs in them recursively.
+type Redactor struct {
+	keys        []*regexp.Regexp
+	values      []*regexp.Regexp
+	replacement string
+}
+
+// NewRedactor returns new redactor.
+func NewRedactor(o RedactOptions) *Redactor {
+	if o.Keys == nil {
+		o.Keys = DefaultRedactKeys
+	}
+	if o.Values == nil {
+		o.Values = DefaultRedactValues
+	}
+	if o.Replacement == "" {
+		o.Replacement = Redacted
+	}
+	return &Redactor{keys: o.Keys, values: o.Values, replacement: o.Replacement}
+}
+
+// Redact returns the fields with their sensitive values replaced, it
+// doesn't change the provided fields.
+func (r *Redactor) Redact(fields []Field) []Field {
+	if len(fields) == 0 {
+		return fields
+	}
+	res := make([]Field, len(fields))
+	for i, f := range fields {
+		res[i] = r.field(f)
+	}
+	return res
+}
+
+func (r *Redactor) field(f Field) Field {
+	switch f.Type {
+	case zapcore.NamespaceType, zapcore.SkipType:
+		return f
+	}
+	if r.sensitiveKey(f.Key) {
+		return String(f.Key, r.replacement)
+	}
+
+	switch f.Type {
+	case zapcore.StringType:
+		if f.Key != ErrorStackLogKey {
+			f.String = r.redactString(f.String)
+		}
+	case zapcore.ByteStringType:
+		if s := string(f.Interface.([]byte)); r.redactString(s) != s {
+			return String(f.Key, r.redactString(s))
+		}
+	case zapcore.StringerType:
+		_, v := FieldToKeyVal(f)
+		if s, ok := v.(string); ok && r.redactString(s) != s {
+			return String(f.Key, r.redactString(s))
+		}
+	case zapcore.ErrorType:
+		if err, ok := f.Interface.(error); ok && r.redactString(err.Error()) != err.Error() {
+			return NamedErr(f.Key, &redactedError{err: err, msg: r.redactString(err.Error())})
+		}
+	case zapcore.ReflectType:
+		if v, redacted := r.value(f.Interface, 0); redacted {
+			return Any(f.Key, v)
+		}
+	case zapcore.ObjectMarshalerType, zapcore.ArrayMarshalerType:
+		_, v := FieldToKeyVal(f)
+		if rv, redacted := r.value(v, 0); redacted {
+			return Any(f.Key, rv)
+		}
+	}
+	return f
+}
+
+// redactedError is an error whose message is redacted. It wraps the
+// original error, so its chain and stack trace are kept, e.g., for the
+// sentry driver.
+type redactedError struct {
+	err error
+	msg string
+}
+
+func (e *redactedError) Error() string {
+	return e.msg
+}
+
+func (e *redactedError) Unwrap() error {
+	return e.err
+}
+
+func (r *Redactor) sensitiveKey(key string) bool {
+	for _, p := range r.keys {
+		if p.MatchString(key) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Redactor) redactString(s string) string {
+	for _, p := range r.values {
+		s = p.ReplaceAllLiteralString(s, r.replacement)
+	}
+	return s
+}
+
+// value returns copy of the value with its sensitive values replaced,
+// and true if it replaced any value. Maps and structs become maps, slices
+// become []any, and values which marshal themselves remain as they are.
+func (r *Redactor) value(v any, depth int) (any, bool) {
+	switch val := v.(type) {
+	case nil:
+		return nil, false
+	case string:
+		s := r.redactString(val)
+		return s, s != val
+	case json.Marshaler, encoding.TextMarshaler, error:
+		return v, false
+	}
+	if depth > maxRedactDepth {
+		return r.replacement, true
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if rv.IsNil() {
+			return v, false
+		}
+		return r.value(rv.Elem().Interface(), depth+1)
+	case reflect.String:
+		s := r.redactString(rv.String())
+		return s, s != rv.String()
+	case reflect.Map:
+		m := make(map[string]any, rv.Len())
+		redacted := false
+		iter := rv.MapRange()
+		for iter.Next() {
+			if r.setKey(m, fmt.Sprint(iter.Key().Interface()), iter.Value().Interface(), depth) {
+				redacted = true
+			}
+		}
+		return m, redacted
+	case reflect.Struct:
+		m := make(map[string]any, rv.NumField())
+		return m, r.structToMap(m, rv, depth)
+	case reflect.Slice, reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.Uint8 { // bytes.
+			return v, false
+		}
+		if rv.Kind() == reflect.Slice && rv.IsNil() {
+			return v, false
+		}
+		s := make([]any, rv.Len())
+		redacted := false
+		for i := range s {
+			var ok bool
+			if s[i], ok = r.value(rv.Index(i).Interface(), depth+1); ok {
+				redacted = true
+			}
+		}
+		return s, redacted
+	}
+	return v, false
+}
+
+// setKey sets the value of the key in the map, and returns true if it
+// replaced any value.
+func (r *Redactor) setKey(m map[string]any, key string, v any, depth int) bool {
+	if r.sensitiveKey(key) {
+		m[key] = r.replacement
+		return true
+	}
+	var redacted bool
+	m[key], redacted = r.value(v, depth+1)
+	return redacted
+}
+
+// structToMap sets exported fields of the struct in the map using their
+// JSON names, and returns true if it replaced any value.
+func (r *Redactor) structToMap(m map[string]any, rv reflect.Value, depth int) bool {
+	redacted := false
+	t := rv.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.PkgPath != "" { // unexported.
+			continue
+		}
+		name, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+
+		fv := rv.Field(i)
+		if sf.Anonymous && name == "" && fv.Kind() == reflect.Struct {
+			// embedded fields are inlined like JSON.
+			if r.structToMap(m, fv, depth) {
+				redacted = true
+			}
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		if r.setKey(m, name, fv.Interface(), depth) {
+			redacted = true
+		}
+	}
+	return redacted
+}
@@ -0,0 +1,174 @@
+package hlog
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrLevelNotChangeable is returned when the logger can not change its
+	// level at runtime.
+	ErrLevelNotChangeable = errors.New("the logger's level can not change at runtime")
+
+	// ErrLoggerNotFound is returned when the global logger doesn't have a
+	// logger with the name.
+	ErrLoggerNotFound = errors.New("logger not found")
+)
+
+// Stack is implemented by loggers which are a stack of named loggers,
+// e.g., the stacked logger driver.
+type Stack interface {
+	// LoggerByName returns logger by its name.
+	// logger can be nil if does not exists.
+	LoggerByName(name string) Logger
+
+	// LoggerNames returns names of the loggers in the stack.
+	LoggerNames() []string
+}
+
+// revert is a pending revert of a level.
+type revert struct {
+	timer *time.Timer
+	// undo reverts the level to its value before the first change, and
+	// returns fields of the reverted level to log.
+	undo func() []Field
+}
+
+// revertKey is the key of a level's pending revert.
+type revertKey struct {
+	// named is true for levels of named loggers.
+	named bool
+	name  string
+}
+
+var reverts = struct {
+	mu sync.Mutex
+	m  map[revertKey]*revert
+}{m: make(map[revertKey]*revert)}
+
+// GetLevel returns level of the global logger.
+func GetLevel() (Level, error) {
+	return LoggerLevel("")
+}
+
+// SetLevel changes level of the global logger.
+func SetLevel(lvl Level) error {
+	return SetLoggerLevel("", lvl, 0)
+}
+
+// LoggerLevel returns level of the logger with the name in the global
+// logger's stack, or level of the global logger if name is empty.
+func LoggerLevel(name string) (Level, error) {
+	s, err := levelSetter(name)
+	if err != nil {
+		return 0, err
+	}
+	return s.Level(), nil
+}
+
+// LoggerLevels returns levels of the loggers in the global logger's stack
+// which can change their level at runtime.
+func LoggerLevels() map[string]Level {
+	levels := make(map[string]Level)
+	stack, ok := GlobalLogger().(Stack)
+	if !ok {
+		return levels
+	}
+
+	for _, name := range stack.LoggerNames() {
+		if s, ok := stack.LoggerByName(name).(LevelSetter); ok {
+			levels[name] = s.Level()
+		}
+	}
+	return levels
+}
+
+// SetLoggerLevel changes level of the logger with the name in the global
+// logger's stack, or level of the global logger if name is empty.
+// If revertAfter is positive, the level reverts after the duration unless
+// it changes again meanwhile. Successive temporary changes revert to the
+// level before the first one.
+func SetLoggerLevel(name string, lvl Level, revertAfter time.Duration) error {
+	s, err := levelSetter(name)
+	if err != nil {
+		return err
+	}
+
+	reverts.mu.Lock()
+	defer reverts.mu.Unlock()
+
+	to := s.Level()
+	changeLevel(revertKey{name: name}, func() { s.SetLevel(lvl) }, func() []Field {
+		s.SetLevel(to)
+		return []Field{String("logger", name), String("level", to.String())}
+	}, revertAfter)
+	return nil
+}
+
+// SetNamedLevelWithRevert sets level of the named loggers with the name or
+// prefix just like SetNamedLevel. If revertAfter is positive, the level
+// reverts after the duration unless it changes again by this function
+// meanwhile, e.g., it unsets the level if we didn't set it before.
+func SetNamedLevelWithRevert(name string, lvl Level, revertAfter time.Duration) {
+	reverts.mu.Lock()
+	defer reverts.mu.Unlock()
+
+	prev, ok := NamedLevels()[name]
+	changeLevel(revertKey{named: true, name: name}, func() { SetNamedLevel(name, lvl) }, func() []Field {
+		if !ok {
+			UnsetNamedLevel(name)
+			return []Field{String("name", name)}
+		}
+		SetNamedLevel(name, prev)
+		return []Field{String("name", name), String("level", prev.String())}
+	}, revertAfter)
+}
+
+// changeLevel changes a level using set. If revertAfter is positive, it
+// reverts the level using undo after the duration. Successive temporary
+// changes revert to the level before the first one. The caller must hold
+// the lock of reverts.
+func changeLevel(k revertKey, set func(), undo func() []Field, revertAfter time.Duration) {
+	if r := reverts.m[k]; r != nil {
+		r.timer.Stop()
+		delete(reverts.m, k)
+		undo = r.undo
+	}
+	set()
+	if revertAfter <= 0 {
+		return
+	}
+
+	logger := GlobalLogger() // don't read the global logger in the timer's goroutine.
+	r := &revert{undo: undo}
+	r.timer = time.AfterFunc(revertAfter, func() {
+		reverts.mu.Lock()
+		defer reverts.mu.Unlock()
+		if reverts.m[k] != r { // the level has changed again.
+			return
+		}
+		delete(reverts.m, k)
+		logger.Info("log level reverted", r.undo()...)
+	})
+	reverts.m[k] = r
+}
+
+func levelSetter(name string) (LevelSetter, error) {
+	l := GlobalLogger()
+	if name != "" {
+		stack, ok := l.(Stack)
+		if !ok {
+			return nil, ErrLoggerNotFound
+		}
+		if l = stack.LoggerByName(name); l == nil {
+			return nil, ErrLoggerNotFound
+		}
+	}
+
+	s, ok := l.(LevelSetter)
+	if !ok {
+		return nil, ErrLevelNotChangeable
+	}
+	return s, nil
+}
@@ -0,0 +1,84 @@
+package hlog
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setGlobal sets the global logger for the test.
+func setGlobal(t *testing.T, l Logger) {
+	prev := GlobalLogger()
+	SetGlobalLogger(l)
+	t.Cleanup(func() { SetGlobalLogger(prev) })
+}
+
+func TestSetLevel(t *testing.T) {
+	setGlobal(t, NewPrinterDriver(InfoLevel))
+	child := With(String("a", "b"))
+
+	require.NoError(t, SetLevel(DebugLevel))
+	lvl, err := GetLevel()
+	require.NoError(t, err)
+	assert.Equal(t, DebugLevel, lvl)
+	assert.True(t, Enabled(DebugLevel))
+	assert.True(t, child.Enabled(DebugLevel), "derived loggers must share the level")
+
+	_, err = LoggerLevel("zap")
+	assert.ErrorIs(t, err, ErrLoggerNotFound)
+	assert.Empty(t, LoggerLevels())
+}
+
+func TestSetLevel_NotChangeable(t *testing.T) {
+	setGlobal(t, recLogger{calls: &[]string{}})
+	assert.ErrorIs(t, SetLevel(DebugLevel), ErrLevelNotChangeable)
+	_, err := GetLevel()
+	assert.ErrorIs(t, err, ErrLevelNotChangeable)
+}
+
+func TestSetLoggerLevel_Revert(t *testing.T) {
+	setGlobal(t, NewPrinterDriver(InfoLevel))
+
+	require.NoError(t, SetLoggerLevel("", DebugLevel, 50*time.Millisecond))
+	require.NoError(t, SetLoggerLevel("", WarnLevel, 50*time.Millisecond))
+	lvl, _ := GetLevel()
+	assert.Equal(t, WarnLevel, lvl)
+	assert.Eventually(t, func() bool {
+		lvl, _ := GetLevel()
+		return lvl == InfoLevel // the level before the first change.
+	}, time.Second, 10*time.Millisecond)
+
+	// A permanent change cancels the pending revert.
+	require.NoError(t, SetLoggerLevel("", DebugLevel, 20*time.Millisecond))
+	require.NoError(t, SetLevel(ErrorLevel))
+	time.Sleep(50 * time.Millisecond)
+	lvl, _ = GetLevel()
+	assert.Equal(t, ErrorLevel, lvl)
+}
+
+func TestAtomicLevel(t *testing.T) {
+	l := NewAtomicLevel(WarnLevel)
+	assert.False(t, l.CanLog(InfoLevel))
+	l.SetLevel(InfoLevel)
+	assert.Equal(t, InfoLevel, l.Level())
+	assert.True(t, l.CanLog(InfoLevel))
+}
+
+func TestSetNamedLevelWithRevert(t *testing.T) {
+	setGlobal(t, NewPrinterDriver(InfoLevel))
+	t.Cleanup(func() { UnsetNamedLevel("revert.a"); UnsetNamedLevel("revert.b") })
+
+	SetNamedLevel("revert.a", WarnLevel)
+	SetNamedLevelWithRevert("revert.a", DebugLevel, 50*time.Millisecond)
+	SetNamedLevelWithRevert("revert.b", DebugLevel, 50*time.Millisecond)
+	SetNamedLevelWithRevert("revert.b", ErrorLevel, 50*time.Millisecond)
+	assert.Equal(t, map[string]Level{"revert.a": DebugLevel, "revert.b": ErrorLevel}, NamedLevels())
+
+	assert.Eventually(t, func() bool {
+		_, ok := NamedLevel("revert.b")
+		lvl, _ := NamedLevel("revert.a")
+		return !ok && lvl == WarnLevel
+	}, time.Second, 10*time.Millisecond)
+}
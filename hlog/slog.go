@@ -0,0 +1,264 @@
+//go:build go1.21
+
+package hlog
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// SlogLevel converts the level to a slog level.
+func SlogLevel(l Level) slog.Level {
+	switch l {
+	case DebugLevel:
+		return slog.LevelDebug
+	case InfoLevel:
+		return slog.LevelInfo
+	case WarnLevel:
+		return slog.LevelWarn
+	}
+	return slog.LevelError
+}
+
+// LevelFromSlog converts a slog level to the level which covers it,
+// e.g., slog.LevelInfo+2 is InfoLevel.
+func LevelFromSlog(l slog.Level) Level {
+	switch {
+	case l < slog.LevelInfo:
+		return DebugLevel
+	case l < slog.LevelWarn:
+		return InfoLevel
+	case l < slog.LevelError:
+		return WarnLevel
+	}
+	return ErrorLevel
+}
+
+// fieldGroup is the object of a slog group, so we can convert it back
+// to the group.
+type fieldGroup []Field
+
+func (g fieldGroup) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	for _, f := range g {
+		f.AddTo(enc)
+	}
+	return nil
+}
+
+// Group returns a field which groups the fields under the key, just
+// like slog groups.
+func Group(key string, fields ...Field) Field {
+	return zap.Object(key, fieldGroup(fields))
+}
+
+// AttrsToFields converts slog attributes to fields. Groups become
+// Group fields and errors become error fields.
+func AttrsToFields(attrs ...slog.Attr) []Field {
+	fields := make([]Field, 0, len(attrs))
+	for _, a := range attrs {
+		fields = appendAttr(fields, a)
+	}
+	return fields
+}
+
+func appendAttr(fields []Field, a slog.Attr) []Field {
+	v := a.Value.Resolve()
+	switch v.Kind() {
+	case slog.KindString:
+		return append(fields, String(a.Key, v.String()))
+	case slog.KindInt64:
+		return append(fields, Int64(a.Key, v.Int64()))
+	case slog.KindUint64:
+		return append(fields, Uint64(a.Key, v.Uint64()))
+	case slog.KindFloat64:
+		return append(fields, zap.Float64(a.Key, v.Float64()))
+	case slog.KindBool:
+		return append(fields, Bool(a.Key, v.Bool()))
+	case slog.KindDuration:
+		return append(fields, Duration(a.Key, v.Duration()))
+	case slog.KindTime:
+		return append(fields, Time(a.Key, v.Time()))
+	case slog.KindGroup:
+		group := AttrsToFields(v.Group()...)
+		if len(group) == 0 {
+			return fields
+		}
+		if a.Key == "" { // inline the group.
+			return append(fields, group...)
+		}
+		return append(fields, Group(a.Key, group...))
+	}
+
+	if a.Key == "" && v.Any() == nil { // an empty attribute.
+		return fields
+	}
+	if err, ok := v.Any().(error); ok {
+		return append(fields, NamedErr(a.Key, err))
+	}
+	return append(fields, Any(a.Key, v.Any()))
+}
+
+// FieldsToAttrs converts fields to slog attributes. Group and object fields
+// become groups, and fields after a namespace field go into its group.
+func FieldsToAttrs(fields ...Field) []slog.Attr {
+	attrs := make([]slog.Attr, 0, len(fields))
+	for i, f := range fields {
+		if f.Type == zapcore.NamespaceType {
+			return append(attrs, slog.Attr{Key: f.Key, Value: slog.GroupValue(FieldsToAttrs(fields[i+1:]...)...)})
+		}
+		if f.Type != zapcore.SkipType {
+			attrs = append(attrs, fieldToAttr(f))
+		}
+	}
+	return attrs
+}
+
+func fieldToAttr(f Field) slog.Attr {
+	switch f.Type {
+	case zapcore.StringType:
+		return slog.String(f.Key, f.String)
+	case zapcore.Int64Type, zapcore.Int32Type, zapcore.Int16Type, zapcore.Int8Type:
+		return slog.Int64(f.Key, f.Integer)
+	case zapcore.Uint64Type, zapcore.Uint32Type, zapcore.Uint16Type, zapcore.Uint8Type, zapcore.UintptrType:
+		return slog.Uint64(f.Key, uint64(f.Integer))
+	case zapcore.Float64Type:
+		return slog.Float64(f.Key, math.Float64frombits(uint64(f.Integer)))
+	case zapcore.Float32Type:
+		return slog.Float64(f.Key, float64(math.Float32frombits(uint32(f.Integer))))
+	case zapcore.BoolType:
+		return slog.Bool(f.Key, f.Integer == 1)
+	case zapcore.DurationType:
+		return slog.Duration(f.Key, time.Duration(f.Integer))
+	case zapcore.TimeType:
+		t := time.Unix(0, f.Integer)
+		if loc, ok := f.Interface.(*time.Location); ok {
+			t = t.In(loc)
+		}
+		return slog.Time(f.Key, t)
+	case zapcore.ErrorType, zapcore.ReflectType:
+		return slog.Any(f.Key, f.Interface)
+	case zapcore.ObjectMarshalerType:
+		if g, ok := f.Interface.(fieldGroup); ok {
+			return slog.Attr{Key: f.Key, Value: slog.GroupValue(FieldsToAttrs(g...)...)}
+		}
+	}
+
+	_, v := FieldToKeyVal(f)
+	return valueToAttr(f.Key, v)
+}
+
+// valueToAttr converts a decoded field value to an attribute, maps of
+// objects become groups.
+func valueToAttr(key string, v any) slog.Attr {
+	m, ok := v.(map[string]any)
+	if !ok {
+		return slog.Any(key, v)
+	}
+
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	attrs := make([]slog.Attr, len(keys))
+	for i, k := range keys {
+		attrs[i] = valueToAttr(k, m[k])
+	}
+	return slog.Attr{Key: key, Value: slog.GroupValue(attrs...)}
+}
+
+// slogHandler is a slog handler which writes to a logger.
+type slogHandler struct {
+	logger Logger
+	// groups are the open groups and attrs are fields of each open group.
+	groups []string
+	attrs  [][]Field
+}
+
+// NewSlogHandler returns a slog handler which writes to the logger, so
+// libraries that use slog can log using hexa loggers, e.g.,
+// slog.New(hlog.NewSlogHandler(hlog.GlobalLogger())).
+func NewSlogHandler(l Logger) slog.Handler {
+	return &slogHandler{logger: l}
+}
+
+func (h *slogHandler) Enabled(_ context.Context, l slog.Level) bool {
+	return h.logger.Enabled(LevelFromSlog(l))
+}
+
+func (h *slogHandler) Handle(ctx context.Context, r slog.Record) error {
+	fields := make([]Field, 0, r.NumAttrs())
+	r.Attrs(func(a slog.Attr) bool {
+		fields = appendAttr(fields, a)
+		return true
+	})
+
+	for i := len(h.groups) - 1; i >= 0; i-- {
+		fields = append(append([]Field{}, h.attrs[i]...), fields...)
+		if len(fields) != 0 {
+			fields = []Field{Group(h.groups[i], fields...)}
+		}
+	}
+
+	l := h.logger
+	if ctx != nil {
+		l = l.WithCtx(ctx)
+	}
+	switch LevelFromSlog(r.Level) {
+	case DebugLevel:
+		l.Debug(r.Message, fields...)
+	case InfoLevel:
+		l.Info(r.Message, fields...)
+	case WarnLevel:
+		l.Warn(r.Message, fields...)
+	default:
+		l.Error(r.Message, fields...)
+	}
+	return nil
+}
+
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	fields := AttrsToFields(attrs...)
+	if len(fields) == 0 {
+		return h
+	}
+	if len(h.groups) == 0 {
+		return &slogHandler{logger: h.logger.With(fields...)}
+	}
+
+	clone := h.clone()
+	last := len(clone.attrs) - 1
+	clone.attrs[last] = append(clone.attrs[last], fields...)
+	return clone
+}
+
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	clone := h.clone()
+	clone.groups = append(clone.groups, name)
+	clone.attrs = append(clone.attrs, nil)
+	return clone
+}
+
+func (h *slogHandler) clone() *slogHandler {
+	attrs := make([][]Field, len(h.attrs))
+	for i, a := range h.attrs {
+		attrs[i] = append([]Field{}, a...)
+	}
+	return &slogHandler{
+		logger: h.logger,
+		groups: append([]string{}, h.groups...),
+		attrs:  attrs,
+	}
+}
+
+var _ slog.Handler = &slogHandler{}
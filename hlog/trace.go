@@ -0,0 +1,59 @@
+package hlog
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Keys of the fields of the OpenTelemetry span, drivers add them to
+// loggers in WithCtx.
+const (
+	TraceIDKey      = "trace_id"
+	SpanIDKey       = "span_id"
+	TraceSampledKey = "trace_sampled"
+)
+
+// TraceFields returns trace and span IDs and the sampled flag of the
+// span in the context, so we can join logs and traces. It returns nil
+// if the context doesn't contain a valid span.
+//
+// Loggers get the span fields in WithCtx, so call WithCtx using the
+// span's context after starting a span, e.g.,
+// hexa.Logger(ctx).WithCtx(ctx), otherwise they log the parent span.
+func TraceFields(ctx context.Context) []Field {
+	if ctx == nil {
+		return nil
+	}
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return nil
+	}
+
+	return []Field{
+		String(TraceIDKey, sc.TraceID().String()),
+		String(SpanIDKey, sc.SpanID().String()),
+		Bool(TraceSampledKey, sc.IsSampled()),
+	}
+}
+
+// ReplaceTraceFields returns the fields with their span fields replaced by
+// the span fields of the context, so a logger that gets a new span in
+// WithCtx doesn't log the span fields of its previous span. It returns the
+// fields as they are if the context doesn't contain a valid span.
+func ReplaceTraceFields(ctx context.Context, fields []Field) []Field {
+	trace := TraceFields(ctx)
+	if trace == nil {
+		return fields
+	}
+
+	res := make([]Field, 0, len(fields)+len(trace))
+	for _, f := range fields {
+		switch f.Key {
+		case TraceIDKey, SpanIDKey, TraceSampledKey:
+		default:
+			res = append(res, f)
+		}
+	}
+	return append(res, trace...)
+}
@@ -0,0 +1,53 @@
+package hlog
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// spanContext returns a context which contains a span.
+func spanContext(sampled bool) context.Context {
+	cfg := trace.SpanContextConfig{
+		TraceID: trace.TraceID{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16},
+		SpanID:  trace.SpanID{1, 2, 3, 4, 5, 6, 7, 8},
+	}
+	if sampled {
+		cfg.TraceFlags = trace.FlagsSampled
+	}
+	return trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(cfg))
+}
+
+func TestTraceFields(t *testing.T) {
+	assert.Nil(t, TraceFields(nil))
+	assert.Nil(t, TraceFields(context.Background()))
+
+	assert.Equal(t, map[string]any{
+		TraceIDKey:      "0102030405060708090a0b0c0d0e0f10",
+		SpanIDKey:       "0102030405060708",
+		TraceSampledKey: true,
+	}, fieldsToMap(TraceFields(spanContext(true))...))
+	assert.Equal(t, false, fieldsToMap(TraceFields(spanContext(false))...)[TraceSampledKey])
+}
+
+func TestPrinterLogger_WithCtx(t *testing.T) {
+	l := NewPrinterDriver(InfoLevel).WithCtx(spanContext(true), String("a", "b")).(*printerLogger)
+	m := fieldsToMap(l.with...)
+	assert.Equal(t, "b", m["a"])
+	assert.Equal(t, "0102030405060708", m[SpanIDKey])
+}
+
+func TestReplaceTraceFields(t *testing.T) {
+	fields := []Field{String(SpanIDKey, "old"), String("a", "b")}
+	assert.Equal(t, fields, ReplaceTraceFields(context.Background(), fields))
+
+	res := ReplaceTraceFields(spanContext(true), fields)
+	assert.Len(t, res, 4)
+	assert.Equal(t, "0102030405060708", fieldsToMap(res...)[SpanIDKey])
+	assert.Equal(t, "old", fields[0].String, "must not change the provided fields")
+
+	l := NewPrinterDriver(InfoLevel).With(String(SpanIDKey, "old")).WithCtx(spanContext(true)).(*printerLogger)
+	assert.Len(t, l.with, 3)
+}
@@ -1,6 +1,7 @@
 package hurl
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	urlpkg "net/url"
@@ -35,6 +36,15 @@ func AuthenticateHeader(header string, tokenType string, token string) RequestOp
 	}
 }
 
+// WithContext sets the request's context, so the request is canceled
+// when the context is done.
+func WithContext(ctx context.Context) RequestOption {
+	return func(req *http.Request) error {
+		*req = *req.WithContext(ctx)
+		return nil
+	}
+}
+
 func QueryParams(params hexa.Map) RequestOption {
 	return func(req *http.Request) error {
 		u := req.URL
@@ -1,18 +1,15 @@
 package probe
 
 import (
-	"fmt"
 	"net/http"
 
-	"github.com/kamva/gutil"
 	"github.com/kamva/hexa"
-	"github.com/kamva/hexa/hlog"
-	"github.com/kamva/tracer"
 )
 
 const (
 	livenessStatusKey  = "liveness_status"
 	readinessStatusKey = "readiness_status"
+	startupStatusKey   = "startup_status"
 )
 
 type healthHandlers struct {
@@ -26,6 +23,9 @@ func RegisterHealthHandlers(ps Server, r hexa.HealthReporter) {
 func (h *healthHandlers) RegisterHandlers(ps Server) {
 	ps.Register("live", "/live", h.livenessHandler, "reports app's liveness")
 	ps.Register("ready", "/ready", h.readinessHandler, "reports app's readiness")
+	if _, ok := h.r.(hexa.StartupReporter); ok {
+		ps.Register("startup", "/startup", h.startupHandler, "reports app's startup")
+	}
 	ps.Register("status", "/status", h.statusHandler, "reports app's status")
 }
 
@@ -53,29 +53,24 @@ func (h *healthHandlers) readinessHandler(w http.ResponseWriter, r *http.Request
 	w.WriteHeader(http.StatusOK)
 }
 
+func (h *healthHandlers) startupHandler(w http.ResponseWriter, r *http.Request) {
+	status := h.r.(hexa.StartupReporter).StartupStatus(r.Context())
+	w.Header().Set(startupStatusKey, string(status))
+
+	if status != hexa.StatusStarted {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
 func (h *healthHandlers) statusHandler(w http.ResponseWriter, r *http.Request) {
 	report := h.r.HealthReport(r.Context())
 	w.Header().Set(livenessStatusKey, string(report.Alive))
 	w.Header().Set(readinessStatusKey, string(report.Ready))
-	w.Header().Set("Content-Type", "application/json")
-
-	resp := hexa.Map{
+	writeJSON(w, http.StatusOK, hexa.Map{
 		"code": "app.status",
 		"data": report,
-	}
-
-	b, err := gutil.Marshal(resp)
-	if err != nil {
-		hlog.Error("error on marshaling health report", hlog.ErrStack(tracer.Trace(err)))
-		resp := fmt.Sprintf(`{"err" : "%s"}`, err.Error())
-		w.WriteHeader(http.StatusInternalServerError)
-		if _, err := w.Write([]byte(resp)); err != nil {
-			hlog.Error("error on writing heal response error", hlog.Err(err))
-		}
-		return
-	}
-
-	if _, err := w.Write(b); err != nil {
-		hlog.Error("error on writing health status", hlog.Err(err))
-	}
+	})
 }
@@ -0,0 +1,112 @@
+package probe
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/kamva/gutil"
+	"github.com/kamva/hexa"
+	"github.com/kamva/hexa/hlog"
+	"github.com/kamva/tracer"
+)
+
+type lockHandlers struct {
+	a      hexa.LockAdmin
+	prefix string
+}
+
+// RegisterLockHandlers registers handlers which list held locks,
+// force-release a lock and clear expired locks of the DLM. The handlers
+// just access locks whose key begins with the prefix, e.g., "locks:".
+// An empty prefix allows all keys.
+func RegisterLockHandlers(ps Server, a hexa.LockAdmin, prefix string) {
+	h := &lockHandlers{a: a, prefix: prefix}
+	ps.Register("locks", "/locks", h.locksHandler, "lists held locks, filter them using the prefix query param")
+	ps.Register("locks-unlock", "/locks/unlock", h.unlockHandler, "force-releases the lock of the key query param (POST)")
+	ps.Register("locks-clear-expired", "/locks/clear-expired", h.clearExpiredHandler, "clears expired locks (POST)")
+}
+
+func (h *lockHandlers) locksHandler(w http.ResponseWriter, r *http.Request) {
+	prefix := r.URL.Query().Get("prefix")
+	if !strings.HasPrefix(prefix, h.prefix) {
+		if !strings.HasPrefix(h.prefix, prefix) {
+			writeJSON(w, http.StatusForbidden, hexa.Map{"err": "the prefix is outside the allowed lock prefix"})
+			return
+		}
+		prefix = h.prefix
+	}
+
+	locks, err := h.a.Locks(r.Context(), prefix)
+	if err != nil {
+		hlog.Error("error on listing locks", hlog.ErrStack(tracer.Trace(err)))
+		writeJSON(w, http.StatusInternalServerError, hexa.Map{"err": err.Error()})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, hexa.Map{"code": "app.locks", "data": locks})
+}
+
+func (h *lockHandlers) unlockHandler(w http.ResponseWriter, r *http.Request) {
+	if !allowPost(w, r) {
+		return
+	}
+	key := r.URL.Query().Get("key")
+	if key == "" {
+		writeJSON(w, http.StatusBadRequest, hexa.Map{"err": "the key query param is required"})
+		return
+	}
+	if !strings.HasPrefix(key, h.prefix) {
+		writeJSON(w, http.StatusForbidden, hexa.Map{"err": "the key is outside the allowed lock prefix"})
+		return
+	}
+
+	hlog.Warn("force-release of the lock is requested on the probe server", hlog.String("key", key), hlog.String("remote_addr", r.RemoteAddr))
+	if err := h.a.ForceUnlock(r.Context(), key); err != nil {
+		hlog.Error("error on force-releasing the lock", hlog.String("key", key), hlog.ErrStack(tracer.Trace(err)))
+		writeJSON(w, http.StatusInternalServerError, hexa.Map{"err": err.Error()})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, hexa.Map{"code": "app.lock_released", "data": hexa.Map{"key": key}})
+}
+
+func (h *lockHandlers) clearExpiredHandler(w http.ResponseWriter, r *http.Request) {
+	if !allowPost(w, r) {
+		return
+	}
+
+	n, err := h.a.ClearExpired(r.Context())
+	if err != nil {
+		hlog.Error("error on clearing expired locks", hlog.ErrStack(tracer.Trace(err)))
+		writeJSON(w, http.StatusInternalServerError, hexa.Map{"err": err.Error()})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, hexa.Map{"code": "app.expired_locks_cleared", "data": hexa.Map{"cleared": n}})
+}
+
+// allowPost writes the method not allowed error if the request's
+// method is not POST.
+func allowPost(w http.ResponseWriter, r *http.Request) bool {
+	if r.Method == http.MethodPost {
+		return true
+	}
+	w.Header().Set("Allow", http.MethodPost)
+	writeJSON(w, http.StatusMethodNotAllowed, hexa.Map{"err": "method not allowed"})
+	return false
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	b, err := gutil.Marshal(v)
+	if err != nil {
+		hlog.Error("error on marshaling the probe response", hlog.ErrStack(tracer.Trace(err)))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if _, err := w.Write(b); err != nil {
+		hlog.Error("error on writing the probe response", hlog.Err(err))
+	}
+}
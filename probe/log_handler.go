@@ -0,0 +1,95 @@
+package probe
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/kamva/hexa"
+	"github.com/kamva/hexa/hlog"
+)
+
+// RegisterLogLevelHandlers registers a handler which reads (GET) and
+// changes (PUT) level of the global logger, the loggers in its stack or
+// the named loggers (see hlog.SetNamedLevel).
+func RegisterLogLevelHandlers(ps Server) {
+	ps.Register("log-level", "/log/level", logLevelHandler,
+		"shows log levels (GET), sets it using the level, logger or name (e.g., mongo*) and revert (e.g., 10m) query params (PUT)")
+}
+
+func logLevelHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		getLogLevel(w)
+	case http.MethodPut:
+		setLogLevel(w, r)
+	default:
+		w.Header().Set("Allow", http.MethodGet+", "+http.MethodPut)
+		writeJSON(w, http.StatusMethodNotAllowed, hexa.Map{"err": "method not allowed"})
+	}
+}
+
+func getLogLevel(w http.ResponseWriter) {
+	loggers := make(map[string]string)
+	for name, lvl := range hlog.LoggerLevels() {
+		loggers[name] = lvl.String()
+	}
+
+	named := make(map[string]string)
+	for name, lvl := range hlog.NamedLevels() {
+		named[name] = lvl.String()
+	}
+
+	data := hexa.Map{"loggers": loggers, "named": named}
+	if lvl, err := hlog.GetLevel(); err == nil {
+		data["level"] = lvl.String()
+	}
+	writeJSON(w, http.StatusOK, hexa.Map{"code": "app.log_level", "data": data})
+}
+
+func setLogLevel(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	lvl, err := hlog.LevelFromString(q.Get("level"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, hexa.Map{"err": err.Error()})
+		return
+	}
+
+	var revert time.Duration
+	if v := q.Get("revert"); v != "" {
+		if revert, err = time.ParseDuration(v); err != nil {
+			writeJSON(w, http.StatusBadRequest, hexa.Map{"err": "invalid revert duration: " + err.Error()})
+			return
+		}
+	}
+
+	logger, name := q.Get("logger"), q.Get("name")
+	if name != "" {
+		if logger != "" {
+			writeJSON(w, http.StatusBadRequest, hexa.Map{"err": "set either the logger or the name"})
+			return
+		}
+		hlog.SetNamedLevelWithRevert(name, lvl, revert)
+	} else if err = hlog.SetLoggerLevel(logger, lvl, revert); err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, hlog.ErrLoggerNotFound) {
+			status = http.StatusNotFound
+		}
+		writeJSON(w, status, hexa.Map{"err": err.Error()})
+		return
+	}
+
+	hlog.Warn("log level is changed on the probe server",
+		hlog.String("logger", logger),
+		hlog.String("name", name),
+		hlog.String("level", lvl.String()),
+		hlog.Duration("revert_after", revert),
+		hlog.String("remote_addr", r.RemoteAddr),
+	)
+	writeJSON(w, http.StatusOK, hexa.Map{"code": "app.log_level_changed", "data": hexa.Map{
+		"logger":       logger,
+		"name":         name,
+		"level":        lvl.String(),
+		"revert_after": revert.String(),
+	}})
+}
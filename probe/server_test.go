@@ -9,6 +9,9 @@ import (
 	"time"
 
 	"github.com/kamva/hexa"
+	"github.com/kamva/hexa/hdlm/memlock"
+	"github.com/kamva/hexa/hlog"
+	"github.com/kamva/hexa/sr"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -38,7 +41,7 @@ func newProbe(t *testing.T, h hexa.Health) *httptest.Server {
 func TestHealthHandlers_Healthy(t *testing.T) {
 	ts := newProbe(t, fakeHealth{alive: hexa.StatusAlive, ready: hexa.StatusReady})
 
-	for _, p := range []string{"/live", "/ready", "/status"} {
+	for _, p := range []string{"/live", "/ready", "/startup", "/status"} {
 		resp, err := http.Get(ts.URL + p)
 		require.NoError(t, err, p)
 		assert.Equal(t, http.StatusOK, resp.StatusCode, p)
@@ -65,6 +68,21 @@ func TestReadinessHandler_Unready(t *testing.T) {
 	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
 }
 
+func TestStartupHandler_NotStarted(t *testing.T) {
+	mux := http.NewServeMux()
+	ps := NewServer(&http.Server{}, mux)
+	r := hexa.NewHealthReporter().(hexa.StartupReporter).AddStartupChecks(fakeHealth{alive: hexa.StatusAlive, ready: hexa.StatusUnReady})
+	RegisterHealthHandlers(ps, r)
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/startup")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	assert.Equal(t, string(hexa.StatusNotStarted), resp.Header.Get("startup_status"))
+}
+
 func TestStatusHandler_ReturnsReport(t *testing.T) {
 	ts := newProbe(t, fakeHealth{alive: hexa.StatusAlive, ready: hexa.StatusReady})
 
@@ -85,6 +103,32 @@ func TestStatusHandler_ReturnsReport(t *testing.T) {
 	assert.Equal(t, "fake", body.Data.Statuses[0].Id)
 }
 
+func TestServicesHandler_ReportsStatuses(t *testing.T) {
+	r := sr.New()
+	r.Register("svc", &struct{}{})
+	require.NoError(t, r.Boot())
+
+	mux := http.NewServeMux()
+	ps := NewServer(&http.Server{}, mux)
+	RegisterServicesHandler(ps, r)
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/services")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	var body struct {
+		Code string               `json:"code"`
+		Data []hexa.ServiceStatus `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, "app.services", body.Code)
+	require.Len(t, body.Data, 1)
+	assert.Equal(t, "svc", body.Data[0].Name)
+	assert.Equal(t, hexa.StateBooted, body.Data[0].State)
+}
+
 func TestDocsHandler_ListsRegisteredHandlers(t *testing.T) {
 	mux := http.NewServeMux()
 	ps := NewServer(&http.Server{}, mux)
@@ -121,3 +165,125 @@ func TestServer_RunAndShutdown(t *testing.T) {
 		t.Fatal("done channel not closed after shutdown")
 	}
 }
+
+func TestLockHandlers(t *testing.T) {
+	dlm, err := memlock.NewDlm(memlock.DlmOptions{DefaultTTL: time.Minute})
+	require.NoError(t, err)
+	ctx := context.Background()
+	require.NoError(t, dlm.NewMutexWithOptions(hexa.MutexOptions{Key: "jobs:a", Owner: "pod-1", TTL: time.Minute}).TryLock(ctx))
+	require.NoError(t, dlm.NewMutexWithOptions(hexa.MutexOptions{Key: "other", Owner: "pod-2", TTL: time.Minute}).TryLock(ctx))
+
+	mux := http.NewServeMux()
+	ps := NewServer(&http.Server{}, mux)
+	RegisterLockHandlers(ps, dlm.(hexa.LockAdmin), "jobs:")
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	locks := func() []hexa.LockInfo {
+		resp, err := http.Get(ts.URL + "/locks?prefix=jobs:")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var body struct {
+			Code string          `json:"code"`
+			Data []hexa.LockInfo `json:"data"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		assert.Equal(t, "app.locks", body.Code)
+		return body.Data
+	}
+	l := locks()
+	require.Len(t, l, 1)
+	assert.Equal(t, "jobs:a", l[0].Key)
+	assert.Equal(t, "pod-1", l[0].Owner)
+
+	resp, err := http.Get(ts.URL + "/locks?prefix=other")
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	resp, err = http.Post(ts.URL+"/locks/unlock?key=other", "", nil)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	l, err = dlm.(hexa.LockAdmin).Locks(ctx, "other")
+	require.NoError(t, err)
+	assert.Len(t, l, 1, "a key outside the prefix must not be released")
+
+	resp, err = http.Get(ts.URL + "/locks/unlock?key=jobs:a")
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+
+	resp, err = http.Post(ts.URL+"/locks/unlock", "", nil)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp, err = http.Post(ts.URL+"/locks/unlock?key=jobs:a", "", nil)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Empty(t, locks())
+
+	resp, err = http.Post(ts.URL+"/locks/clear-expired", "", nil)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestLogLevelHandler(t *testing.T) {
+	prev := hlog.GlobalLogger()
+	hlog.SetGlobalLogger(hlog.NewPrinterDriver(hlog.InfoLevel))
+	defer hlog.SetGlobalLogger(prev)
+
+	mux := http.NewServeMux()
+	RegisterLogLevelHandlers(NewServer(&http.Server{}, mux))
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	do := func(method, query string) (int, map[string]any) {
+		req, err := http.NewRequest(method, ts.URL+"/log/level"+query, nil)
+		require.NoError(t, err)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		return resp.StatusCode, body
+	}
+
+	status, body := do(http.MethodGet, "")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "info", body["data"].(map[string]any)["level"])
+
+	status, _ = do(http.MethodPut, "?level=verbose")
+	assert.Equal(t, http.StatusBadRequest, status)
+	status, _ = do(http.MethodPut, "?level=debug&revert=soon")
+	assert.Equal(t, http.StatusBadRequest, status)
+	status, _ = do(http.MethodPut, "?level=debug&logger=zap")
+	assert.Equal(t, http.StatusNotFound, status)
+	status, _ = do(http.MethodPost, "?level=debug")
+	assert.Equal(t, http.StatusMethodNotAllowed, status)
+
+	status, body = do(http.MethodPut, "?level=debug&revert=50ms")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "app.log_level_changed", body["code"])
+	assert.True(t, hlog.Enabled(hlog.DebugLevel))
+	assert.Eventually(t, func() bool {
+		return !hlog.GlobalLogger().Enabled(hlog.DebugLevel)
+	}, time.Second, 10*time.Millisecond)
+
+	status, _ = do(http.MethodPut, "?level=debug&name=probe&logger=zap")
+	assert.Equal(t, http.StatusBadRequest, status)
+	status, _ = do(http.MethodPut, "?level=debug&name=probe&revert=50ms")
+	assert.Equal(t, http.StatusOK, status)
+	_, body = do(http.MethodGet, "")
+	assert.Equal(t, map[string]any{"probe": "debug"}, body["data"].(map[string]any)["named"])
+	assert.Eventually(t, func() bool {
+		_, ok := hlog.NamedLevel("probe")
+		return !ok
+	}, time.Second, 10*time.Millisecond)
+}
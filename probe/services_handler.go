@@ -0,0 +1,22 @@
+package probe
+
+import (
+	"net/http"
+
+	"github.com/kamva/hexa"
+)
+
+// RegisterServicesHandler registers a handler which reports lifecycle
+// state of the service registry's services.
+func RegisterServicesHandler(ps Server, r hexa.ServiceStatusReporter) {
+	ps.Register("services", "/services", servicesHandler(r), "reports lifecycle state of app's services")
+}
+
+func servicesHandler(r hexa.ServiceStatusReporter) Handler {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, hexa.Map{
+			"code": "app.services",
+			"data": r.Statuses(),
+		})
+	}
+}
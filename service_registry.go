@@ -1,6 +1,9 @@
 package hexa
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 type Service any // Currently Service interface does not needs to implement anything.
 
@@ -35,16 +38,118 @@ type Runnable interface {
 	Run() (done <-chan error, err error)
 }
 
+// ContextBootable is implemented by Bootable services which can cancel
+// their boot. The service registry boots them using BootContext with a
+// context that is canceled when their boot timeout expires.
+type ContextBootable interface {
+	BootContext(ctx context.Context) error
+}
+
 type Shutdownable interface {
 	Shutdown(context.Context) error
 }
 
+type RestartMode string
+
+const (
+	// RestartNever never restarts the service, an error in its run shutdowns the registry.
+	RestartNever RestartMode = "never"
+	// RestartOnFailure restarts the service when its run is done with an error.
+	RestartOnFailure RestartMode = "on-failure"
+	// RestartAlways restarts the service whenever its run is done.
+	RestartAlways RestartMode = "always"
+)
+
+// RestartPolicy specifies how the service registry restarts a Runnable
+// service when its run is done. The zero value never restarts the service.
+type RestartPolicy struct {
+	Mode RestartMode
+	// MaxRestarts is the maximum number of restarts. zero means unlimited.
+	MaxRestarts int
+	// Backoff is the delay before the first restart and doubles on each
+	// restart. The registry uses its default value if it's zero.
+	Backoff time.Duration
+	// MaxBackoff caps the delay between restarts. zero means no cap.
+	MaxBackoff time.Duration
+}
+
+// RetryPolicy specifies retries of a failed operation. The zero value
+// doesn't retry.
+type RetryPolicy struct {
+	// MaxRetries is the maximum number of retries.
+	MaxRetries int
+	// Backoff is the delay before the first retry and doubles on each
+	// retry. The registry uses its default value if it's zero.
+	Backoff time.Duration
+	// MaxBackoff caps the delay between retries. zero means no cap.
+	MaxBackoff time.Duration
+}
+
+// HealthChecks specifies the checks of a HealthReporter that
+// a service's health is added to.
+type HealthChecks uint8
+
+const (
+	LivenessCheck HealthChecks = 1 << iota
+	ReadinessCheck
+	StatusCheck
+
+	// AllHealthChecks adds the health to all checks, just like AddToChecks.
+	AllHealthChecks = LivenessCheck | ReadinessCheck | StatusCheck
+)
+
+// Has returns true if c contains the check.
+func (c HealthChecks) Has(check HealthChecks) bool {
+	return c&check != 0
+}
+
 // Descriptor describes the service.
 type Descriptor struct {
 	Name     string
 	Instance Service
 	Priority int
-	Health   Health
+	// DependsOn contains names of the services that this service depends on.
+	// The registry boots a service after its dependencies and shuts it down
+	// before them. Priority just breaks ties between independent services.
+	DependsOn []string
+	Health    Health
+	// HealthChecks specifies checks of the registry's health reporter
+	// that Health is added to. zero means all checks.
+	HealthChecks HealthChecks
+	// BootTimeout limits the service boot. zero means no timeout.
+	// Please note a timed-out boot is not retried, because it may
+	// still be running unless the service is a ContextBootable.
+	BootTimeout time.Duration
+	// BootRetry specifies retries of a failed boot, e.g., for services
+	// that wait for their external dependencies.
+	BootRetry RetryPolicy
+	// ShutdownTimeout limits the service shutdown. zero means no timeout.
+	ShutdownTimeout time.Duration
+	RestartPolicy   RestartPolicy
+}
+
+type ServiceState string
+
+const (
+	StateRegistered ServiceState = "registered"
+	StateBooting    ServiceState = "booting"
+	StateBooted     ServiceState = "booted"
+	StateRunning    ServiceState = "running"
+	StateStopping   ServiceState = "stopping"
+	StateStopped    ServiceState = "stopped"
+	StateFailed     ServiceState = "failed"
+)
+
+// ServiceStatus is the lifecycle state of a service.
+type ServiceStatus struct {
+	Name  string       `json:"name"`
+	State ServiceState `json:"state"`
+	// Since is the time that the service entered its current state.
+	Since time.Time `json:"since"`
+	// Timestamps contains the last time that the service entered each state.
+	Timestamps map[ServiceState]time.Time `json:"timestamps"`
+	// Error is the error of the failed state.
+	Error string `json:"error,omitempty"`
 }
 
 type ServiceRegistry interface {
@@ -61,3 +166,22 @@ type ServiceRegistry interface {
 	// Service method should return nil if service not found.
 	Service(name string) Service
 }
+
+// ServiceRunner is implemented by service registries which run and
+// supervise their Runnable services.
+type ServiceRunner interface {
+	// Run runs all Runnable services in their priority order and
+	// supervises them. It should be called after Boot.
+	Run() error
+	// Wait blocks until the registry shuts down and returns the
+	// first fatal error of the services, or nil if it was a
+	// graceful shutdown.
+	Wait() error
+}
+
+// ServiceStatusReporter is implemented by service registries which
+// track lifecycle state of their services.
+type ServiceStatusReporter interface {
+	// Statuses returns lifecycle state of services ordered like Descriptors.
+	Statuses() []ServiceStatus
+}
@@ -0,0 +1,58 @@
+package sr
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrBootTimeout is returned when a service boot exceeds its timeout.
+var ErrBootTimeout = errors.New("service boot timed out")
+
+// ServiceError annotates an error of a service lifecycle with the service name.
+type ServiceError struct {
+	Service string
+	Err     error
+}
+
+func (e *ServiceError) Error() string {
+	return fmt.Sprintf("service %s: %v", e.Service, e.Err)
+}
+
+func (e *ServiceError) Unwrap() error {
+	return e.Err
+}
+
+// ShutdownError aggregates errors of all services that failed to shut down
+// and the registry's shutdown hooks. Services errors are *ServiceError.
+type ShutdownError struct {
+	Errors []error
+}
+
+func (e *ShutdownError) Error() string {
+	l := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		l[i] = err.Error()
+	}
+	return "shutdown failed: " + strings.Join(l, "; ")
+}
+
+// Is reports whether any of the services errors matches the target.
+func (e *ShutdownError) Is(target error) bool {
+	for _, err := range e.Errors {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first services error that matches the target.
+func (e *ShutdownError) As(target any) bool {
+	for _, err := range e.Errors {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,180 @@
+package sr
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/kamva/hexa"
+	"github.com/kamva/tracer"
+)
+
+// graph is the dependency graph of the services.
+type graph struct {
+	// order is the topological order of the descriptors, ties are broken
+	// by priority. so without any dependency it's just the priority order.
+	order []*hexa.Descriptor
+	// deps contains dependencies of each descriptor.
+	deps map[*hexa.Descriptor][]*hexa.Descriptor
+	// dependents contains descriptors that depend on each descriptor.
+	dependents map[*hexa.Descriptor][]*hexa.Descriptor
+}
+
+// newGraph builds the dependency graph of the descriptors. descriptors
+// must be sorted by their priority.
+func newGraph(ds []*hexa.Descriptor) (*graph, error) {
+	byName := make(map[string]*hexa.Descriptor, len(ds))
+	for _, d := range ds {
+		byName[d.Name] = d
+	}
+
+	g := &graph{
+		order:      make([]*hexa.Descriptor, 0, len(ds)),
+		deps:       make(map[*hexa.Descriptor][]*hexa.Descriptor, len(ds)),
+		dependents: make(map[*hexa.Descriptor][]*hexa.Descriptor, len(ds)),
+	}
+	for _, d := range ds {
+		for _, name := range d.DependsOn {
+			dep, ok := byName[name]
+			if !ok {
+				return nil, tracer.Trace(fmt.Errorf("service %s depends on unknown service %s", d.Name, name))
+			}
+			g.deps[d] = append(g.deps[d], dep)
+			g.dependents[dep] = append(g.dependents[dep], d)
+		}
+	}
+
+	placed := make(map[*hexa.Descriptor]bool, len(ds))
+	for len(g.order) < len(ds) {
+		next := g.nextReady(ds, placed)
+		if next == nil {
+			return nil, tracer.Trace(fmt.Errorf("dependency cycle between services: %s", g.cycle(ds, placed)))
+		}
+		placed[next] = true
+		g.order = append(g.order, next)
+	}
+
+	return g, nil
+}
+
+// nextReady returns the descriptor with the lowest priority whose
+// dependencies are all placed.
+func (g *graph) nextReady(ds []*hexa.Descriptor, placed map[*hexa.Descriptor]bool) *hexa.Descriptor {
+	for _, d := range ds {
+		if placed[d] {
+			continue
+		}
+
+		ready := true
+		for _, dep := range g.deps[d] {
+			if !placed[dep] {
+				ready = false
+				break
+			}
+		}
+		if ready {
+			return d
+		}
+	}
+	return nil
+}
+
+// cycle returns a readable dependency cycle between the
+// descriptors that are not placed (e.g., "a -> b -> a").
+func (g *graph) cycle(ds []*hexa.Descriptor, placed map[*hexa.Descriptor]bool) string {
+	var start *hexa.Descriptor
+	for _, d := range ds {
+		if !placed[d] {
+			start = d
+			break
+		}
+	}
+
+	// Each unplaced descriptor has at least one unplaced dependency,
+	// so walking them must reach a descriptor that we've seen.
+	seen := make(map[*hexa.Descriptor]int)
+	var path []*hexa.Descriptor
+	for d := start; ; {
+		if i, ok := seen[d]; ok {
+			path = append(path[i:], d)
+			break
+		}
+		seen[d] = len(path)
+		path = append(path, d)
+		for _, dep := range g.deps[d] {
+			if !placed[dep] {
+				d = dep
+				break
+			}
+		}
+	}
+
+	names := make([]string, len(path))
+	for i, d := range path {
+		names[i] = d.Name
+	}
+	return strings.Join(names, " -> ")
+}
+
+// reverse returns the reverse of the topological order.
+func (g *graph) reverse() []*hexa.Descriptor {
+	l := make([]*hexa.Descriptor, len(g.order))
+	for i, d := range g.order {
+		l[len(l)-1-i] = d
+	}
+	return l
+}
+
+// runInParallel runs fn for every descriptor in its own goroutine as soon
+// as fn is done for all descriptors that it waits for. After the first
+// error, it doesn't run fn for the remained descriptors and returns
+// the error.
+func runInParallel(ds []*hexa.Descriptor, waitFor map[*hexa.Descriptor][]*hexa.Descriptor, fn func(d *hexa.Descriptor) error) error {
+	done := make(map[*hexa.Descriptor]chan struct{}, len(ds))
+	for _, d := range ds {
+		done[d] = make(chan struct{})
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var firstErr error
+
+	for _, d := range ds {
+		wg.Add(1)
+		go func(d *hexa.Descriptor) {
+			defer wg.Done()
+			defer close(done[d])
+			for _, dep := range waitFor[d] {
+				<-done[dep]
+			}
+
+			mu.Lock()
+			failed := firstErr != nil
+			mu.Unlock()
+			if failed {
+				return
+			}
+
+			if err := fn(d); err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = err
+				}
+				mu.Unlock()
+			}
+		}(d)
+	}
+
+	wg.Wait()
+	return firstErr
+}
+
+// runInOrder runs fn for descriptors one by one and returns the first error.
+func runInOrder(ds []*hexa.Descriptor, fn func(d *hexa.Descriptor) error) error {
+	for _, d := range ds {
+		if err := fn(d); err != nil {
+			return err
+		}
+	}
+	return nil
+}
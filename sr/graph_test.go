@@ -0,0 +1,144 @@
+package sr
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kamva/hexa"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewGraph_OrdersByDependenciesThenPriority(t *testing.T) {
+	r := New()
+	r.RegisterByDescriptor(&hexa.Descriptor{Name: "app", Instance: &struct{}{}, Priority: 1, DependsOn: []string{"db", "cache"}})
+	r.RegisterByDescriptor(&hexa.Descriptor{Name: "cache", Instance: &struct{}{}, Priority: 3})
+	r.RegisterByDescriptor(&hexa.Descriptor{Name: "db", Instance: &struct{}{}, Priority: 2, DependsOn: []string{"config"}})
+	r.RegisterByDescriptor(&hexa.Descriptor{Name: "config", Instance: &struct{}{}, Priority: 4})
+
+	g, err := newGraph(r.Descriptors())
+	require.NoError(t, err)
+	// cache and config are ready first, cache has the lower priority.
+	assert.Equal(t, []string{"cache", "config", "db", "app"}, names(g.order))
+	assert.Equal(t, []string{"app", "db", "config", "cache"}, names(g.reverse()))
+}
+
+func TestNewGraph_UnknownDependency(t *testing.T) {
+	_, err := newGraph([]*hexa.Descriptor{{Name: "a", DependsOn: []string{"missing"}}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "service a depends on unknown service missing")
+}
+
+func TestNewGraph_Cycle(t *testing.T) {
+	_, err := newGraph([]*hexa.Descriptor{
+		{Name: "root"},
+		{Name: "a", DependsOn: []string{"root", "b"}},
+		{Name: "b", DependsOn: []string{"c"}},
+		{Name: "c", DependsOn: []string{"a"}},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "a -> b -> c -> a")
+}
+
+func TestBoot_CycleReturnsError(t *testing.T) {
+	rec := &recorder{}
+	r := New()
+	r.RegisterByDescriptor(&hexa.Descriptor{Name: "a", Instance: &svc{name: "a", rec: rec}, DependsOn: []string{"b"}})
+	r.RegisterByDescriptor(&hexa.Descriptor{Name: "b", Instance: &svc{name: "b", rec: rec}, DependsOn: []string{"a"}})
+
+	assert.Error(t, r.Boot())
+	assert.Empty(t, rec.booted)
+}
+
+func TestShutdown_CycleReturnsError(t *testing.T) {
+	rec := &recorder{}
+	r := New()
+	r.RegisterByDescriptor(&hexa.Descriptor{Name: "a", Instance: &svc{name: "a", rec: rec}, DependsOn: []string{"b"}})
+	r.RegisterByDescriptor(&hexa.Descriptor{Name: "b", Instance: &svc{name: "b", rec: rec}, DependsOn: []string{"a"}})
+
+	err := r.Shutdown(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "dependency cycle")
+	assert.Empty(t, rec.shutdown)
+}
+
+func TestBootAndShutdown_FollowDependencies(t *testing.T) {
+	rec := &recorder{}
+	r := New()
+	r.RegisterByDescriptor(&hexa.Descriptor{Name: "a", Instance: &svc{name: "a", rec: rec}, Priority: 1, DependsOn: []string{"b"}})
+	r.RegisterByDescriptor(&hexa.Descriptor{Name: "b", Instance: &svc{name: "b", rec: rec}, Priority: 2})
+
+	require.NoError(t, r.Boot())
+	assert.Equal(t, []string{"b", "a"}, rec.booted)
+
+	require.NoError(t, r.Shutdown(context.Background()))
+	assert.Equal(t, []string{"a", "b"}, rec.shutdown)
+}
+
+// barrierSvc boots only when all other barrier services are booting
+// at the same time, so it deadlocks unless they boot in parallel.
+type barrierSvc struct {
+	wg  *sync.WaitGroup
+	mu  *sync.Mutex
+	rec *[]string
+	id  string
+}
+
+func (s *barrierSvc) Boot() error {
+	s.wg.Done()
+	s.wg.Wait()
+	return nil
+}
+
+func (s *barrierSvc) Shutdown(context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	*s.rec = append(*s.rec, s.id)
+	return nil
+}
+
+func TestParallelLifecycle(t *testing.T) {
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var shutdown []string
+	wg.Add(2)
+	var single sync.WaitGroup
+	single.Add(1)
+
+	r := New(WithParallelLifecycle())
+	r.Register("a", &barrierSvc{wg: &wg, mu: &mu, rec: &shutdown, id: "a"})
+	r.Register("b", &barrierSvc{wg: &wg, mu: &mu, rec: &shutdown, id: "b"})
+	r.RegisterByDescriptor(&hexa.Descriptor{
+		Name:      "c",
+		Instance:  &barrierSvc{wg: &single, mu: &mu, rec: &shutdown, id: "c"},
+		DependsOn: []string{"a", "b"},
+	})
+
+	booted := make(chan error, 1)
+	go func() { booted <- r.Boot() }()
+	select {
+	case err := <-booted:
+		require.NoError(t, err)
+	case <-time.After(3 * time.Second):
+		t.Fatal("independent services did not boot in parallel")
+	}
+
+	require.NoError(t, r.Shutdown(context.Background()))
+	require.Len(t, shutdown, 3)
+	assert.Equal(t, "c", shutdown[0]) // dependent service shuts down first.
+}
+
+func TestRunInParallel_StopsAfterError(t *testing.T) {
+	a := &hexa.Descriptor{Name: "a"}
+	b := &hexa.Descriptor{Name: "b"}
+	var ran []string
+
+	err := runInParallel([]*hexa.Descriptor{a, b}, map[*hexa.Descriptor][]*hexa.Descriptor{b: {a}}, func(d *hexa.Descriptor) error {
+		ran = append(ran, d.Name)
+		return assert.AnError
+	})
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Equal(t, []string{"a"}, ran)
+}
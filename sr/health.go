@@ -0,0 +1,134 @@
+package sr
+
+import (
+	"context"
+	"sync"
+
+	"github.com/kamva/hexa"
+)
+
+// healthReporter is a health reporter which adds health of the registry's
+// services to its checks, including services that are registered later.
+type healthReporter struct {
+	hexa.HealthReporter
+	r hexa.ServiceRegistry
+
+	mu    sync.Mutex
+	added map[*hexa.Descriptor]hexa.Health
+}
+
+// serviceHealth wraps health of a service, so we can remove exactly
+// the checks that we added for it.
+type serviceHealth struct {
+	hexa.Health
+}
+
+// NewHealthReporter returns a health reporter which checks health of all
+// services of the registry (the Descriptor.Health field) according to their
+// Descriptor.HealthChecks. You can add other checks to it just like any
+// other health reporter.
+func NewHealthReporter(r hexa.ServiceRegistry) hexa.HealthReporter {
+	return &healthReporter{
+		HealthReporter: hexa.NewHealthReporter(),
+		r:              r,
+		added:          make(map[*hexa.Descriptor]hexa.Health),
+	}
+}
+
+// sync adds health of new services to the checks and removes checks of
+// the services that are not registered anymore (e.g., overwritten).
+func (h *healthReporter) sync() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	registered := make(map[*hexa.Descriptor]struct{})
+	for _, d := range h.r.Descriptors() {
+		if h.r.Descriptor(d.Name) != d {
+			continue
+		}
+		registered[d] = struct{}{}
+		if _, ok := h.added[d]; ok || d.Health == nil {
+			continue
+		}
+		health := &serviceHealth{Health: d.Health}
+		h.added[d] = health
+
+		checks := d.HealthChecks
+		if checks == 0 {
+			checks = hexa.AllHealthChecks
+		}
+		if checks.Has(hexa.LivenessCheck) {
+			h.HealthReporter.AddLivenessChecks(health)
+		}
+		if checks.Has(hexa.ReadinessCheck) {
+			h.HealthReporter.AddReadinessChecks(health)
+		}
+		if checks.Has(hexa.StatusCheck) {
+			h.HealthReporter.AddStatusChecks(health)
+		}
+	}
+
+	var removed []hexa.Health
+	for d, health := range h.added {
+		if _, ok := registered[d]; !ok {
+			removed = append(removed, health)
+			delete(h.added, d)
+		}
+	}
+	if len(removed) != 0 {
+		h.HealthReporter.(hexa.HealthCheckRemover).RemoveChecks(removed...)
+	}
+}
+
+func (h *healthReporter) AddLivenessChecks(l ...hexa.Health) hexa.HealthReporter {
+	h.HealthReporter.AddLivenessChecks(l...)
+	return h
+}
+
+func (h *healthReporter) AddReadinessChecks(l ...hexa.Health) hexa.HealthReporter {
+	h.HealthReporter.AddReadinessChecks(l...)
+	return h
+}
+
+func (h *healthReporter) AddStatusChecks(l ...hexa.Health) hexa.HealthReporter {
+	h.HealthReporter.AddStatusChecks(l...)
+	return h
+}
+
+func (h *healthReporter) AddStartupChecks(l ...hexa.Health) hexa.HealthReporter {
+	h.HealthReporter.(hexa.StartupReporter).AddStartupChecks(l...)
+	return h
+}
+
+func (h *healthReporter) AddToChecks(l ...hexa.Health) hexa.HealthReporter {
+	h.HealthReporter.AddToChecks(l...)
+	return h
+}
+
+func (h *healthReporter) LivenessStatus(ctx context.Context) hexa.LivenessStatus {
+	h.sync()
+	return h.HealthReporter.LivenessStatus(ctx)
+}
+
+func (h *healthReporter) ReadinessStatus(ctx context.Context) hexa.ReadinessStatus {
+	h.sync()
+	return h.HealthReporter.ReadinessStatus(ctx)
+}
+
+func (h *healthReporter) StartupStatus(ctx context.Context) hexa.StartupStatus {
+	h.sync()
+	return h.HealthReporter.(hexa.StartupReporter).StartupStatus(ctx)
+}
+
+func (h *healthReporter) Subscribe(size int) (<-chan hexa.HealthEvent, func()) {
+	return h.HealthReporter.(hexa.HealthSubscriber).Subscribe(size)
+}
+
+func (h *healthReporter) HealthReport(ctx context.Context) hexa.HealthReport {
+	h.sync()
+	return h.HealthReporter.HealthReport(ctx)
+}
+
+var _ hexa.HealthReporter = &healthReporter{}
+var _ hexa.StartupReporter = &healthReporter{}
+var _ hexa.HealthSubscriber = &healthReporter{}
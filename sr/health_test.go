@@ -0,0 +1,82 @@
+package sr
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kamva/hexa"
+	"github.com/stretchr/testify/assert"
+)
+
+// unhealthySvc is dead and unready.
+type unhealthySvc struct {
+	id string
+}
+
+func (h *unhealthySvc) HealthIdentifier() string                           { return h.id }
+func (h *unhealthySvc) LivenessStatus(context.Context) hexa.LivenessStatus { return hexa.StatusDead }
+func (h *unhealthySvc) ReadinessStatus(context.Context) hexa.ReadinessStatus {
+	return hexa.StatusUnReady
+}
+func (h *unhealthySvc) HealthStatus(context.Context) hexa.HealthStatus {
+	return hexa.HealthStatus{Id: h.id, Alive: hexa.StatusDead, Ready: hexa.StatusUnReady}
+}
+
+func TestHealthReporter_PicksUpLaterRegistrations(t *testing.T) {
+	ctx := context.Background()
+	r := New()
+	r.Register("a", &healthSvc{id: "a"})
+	r.Register("plain", &struct{}{})
+	h := NewHealthReporter(r)
+
+	assert.Equal(t, hexa.StatusAlive, h.LivenessStatus(ctx))
+	assert.Equal(t, hexa.StatusReady, h.ReadinessStatus(ctx))
+
+	r.Register("b", &unhealthySvc{id: "b"})
+	assert.Equal(t, hexa.StatusDead, h.LivenessStatus(ctx))
+	assert.Equal(t, hexa.StatusUnReady, h.ReadinessStatus(ctx))
+
+	report := h.HealthReport(ctx)
+	assert.Len(t, report.Statuses, 2) // each service is added once.
+	assert.Equal(t, "a", report.Statuses[0].Id)
+	assert.Equal(t, "b", report.Statuses[1].Id)
+}
+
+func TestHealthReporter_HonoursHealthChecks(t *testing.T) {
+	ctx := context.Background()
+	r := New()
+	r.RegisterByDescriptor(&hexa.Descriptor{
+		Name:         "warmup",
+		Instance:     &unhealthySvc{id: "warmup"},
+		HealthChecks: hexa.ReadinessCheck | hexa.StatusCheck,
+	})
+	h := NewHealthReporter(r).AddToChecks(&healthSvc{id: "manual"})
+
+	assert.Equal(t, hexa.StatusAlive, h.LivenessStatus(ctx))
+	assert.Equal(t, hexa.StatusUnReady, h.ReadinessStatus(ctx))
+	assert.Len(t, h.HealthReport(ctx).Statuses, 2)
+}
+
+func TestHealthReporter_MultiSearchRegistry(t *testing.T) {
+	ctx := context.Background()
+	primary := New()
+	secondary := New()
+	h := NewHealthReporter(NewMultiSearchRegistry(primary, primary, secondary))
+
+	secondary.Register("b", &unhealthySvc{id: "b"})
+	assert.Equal(t, hexa.StatusDead, h.LivenessStatus(ctx))
+}
+
+func TestHealthReporter_DropsOverwrittenServices(t *testing.T) {
+	ctx := context.Background()
+	r := New()
+	r.Register("a", &unhealthySvc{id: "a"})
+	h := NewHealthReporter(r)
+	assert.Equal(t, hexa.StatusDead, h.LivenessStatus(ctx))
+
+	r.Register("a", &healthSvc{id: "a"})
+	assert.Equal(t, hexa.StatusAlive, h.LivenessStatus(ctx))
+	assert.Equal(t, hexa.StatusReady, h.ReadinessStatus(ctx))
+
+	assert.Len(t, h.HealthReport(ctx).Statuses, 1)
+}
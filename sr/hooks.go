@@ -0,0 +1,117 @@
+package sr
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kamva/hexa"
+)
+
+// Phase is a lifecycle phase of services.
+type Phase string
+
+const (
+	PhaseBoot     Phase = "boot"
+	PhaseRun      Phase = "run"
+	PhaseShutdown Phase = "shutdown"
+)
+
+// Stage specifies whether a hook runs before or after a phase.
+type Stage string
+
+const (
+	StageBefore Stage = "before"
+	StageAfter  Stage = "after"
+)
+
+// HookEvent describes the lifecycle event that a hook runs for.
+type HookEvent struct {
+	Phase Phase
+	Stage Stage
+	// Service is the service's descriptor, it's nil for the registry's events.
+	Service *hexa.Descriptor
+	// Err is the phase's error in the after stage.
+	Err error
+}
+
+// Hook runs on lifecycle events. An error of a hook is handled just like an
+// error of the service (or the registry) in that phase, e.g., an error in a
+// boot hook fails the boot, and an error in a shutdown hook doesn't stop the
+// shutdown but is returned from Shutdown.
+// Boot and run hooks get a background context, shutdown hooks get the
+// shutdown context.
+type Hook func(ctx context.Context, e HookEvent) error
+
+// WithHook adds a hook which runs on every lifecycle event of the
+// registry and its services.
+func WithHook(h Hook) Option {
+	return func(o *options) {
+		o.hooks = append(o.hooks, h)
+	}
+}
+
+// WithRegistryHook adds a hook which runs before or after a phase of
+// the whole registry, e.g., to flip readiness to UNREADY as soon as
+// shutdown begins or to flush logs after all services shut down.
+func WithRegistryHook(p Phase, s Stage, h Hook) Option {
+	return WithHook(func(ctx context.Context, e HookEvent) error {
+		if e.Service != nil || e.Phase != p || e.Stage != s {
+			return nil
+		}
+		return h(ctx, e)
+	})
+}
+
+// WithServiceHook adds a hook which runs before or after a phase of
+// each service. it runs only for services that implement that phase
+// (e.g., boot hooks run for Bootable services).
+func WithServiceHook(p Phase, s Stage, h Hook) Option {
+	return WithHook(func(ctx context.Context, e HookEvent) error {
+		if e.Service == nil || e.Phase != p || e.Stage != s {
+			return nil
+		}
+		return h(ctx, e)
+	})
+}
+
+// runHooks runs all hooks and returns the first error.
+func (r *serviceRegistry) runHooks(ctx context.Context, e HookEvent) error {
+	var first error
+	for _, h := range r.o.hooks {
+		if err := h(ctx, e); err != nil && first == nil {
+			first = err
+		}
+	}
+
+	if first == nil {
+		return nil
+	}
+
+	err := fmt.Errorf("%s %s hook: %w", e.Stage, e.Phase, first)
+	if e.Service != nil {
+		return &ServiceError{Service: e.Service.Name, Err: err}
+	}
+	return fmt.Errorf("registry %w", err)
+}
+
+// withHooks runs fn between the before and after hooks of the boot or
+// run phase. d is nil for the registry's phases.
+func (r *serviceRegistry) withHooks(ctx context.Context, p Phase, d *hexa.Descriptor, fn func() error) error {
+	if err := r.runHooks(ctx, HookEvent{Phase: p, Stage: StageBefore, Service: d}); err != nil {
+		r.hookFailed(d, err)
+		return err
+	}
+
+	err := fn()
+	if hookErr := r.runHooks(ctx, HookEvent{Phase: p, Stage: StageAfter, Service: d, Err: err}); hookErr != nil && err == nil {
+		r.hookFailed(d, hookErr)
+		return hookErr
+	}
+	return err
+}
+
+func (r *serviceRegistry) hookFailed(d *hexa.Descriptor, err error) {
+	if d != nil {
+		r.st.set(d.Name, hexa.StateFailed, err)
+	}
+}
@@ -0,0 +1,113 @@
+package sr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/kamva/hexa"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type hookRecorder struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (h *hookRecorder) hook(_ context.Context, e HookEvent) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	name := "registry"
+	if e.Service != nil {
+		name = e.Service.Name
+	}
+	h.events = append(h.events, fmt.Sprintf("%s %s %s", e.Stage, e.Phase, name))
+	return nil
+}
+
+func TestHooks_RunAroundLifecycle(t *testing.T) {
+	h := &hookRecorder{}
+	r := New(WithHook(h.hook))
+	r.Register("a", &svc{name: "a", rec: &recorder{}})
+	r.Register("run", &runSvc{})
+
+	require.NoError(t, r.Boot())
+	require.NoError(t, r.Run())
+	require.NoError(t, r.Shutdown(context.Background()))
+
+	assert.Equal(t, []string{
+		"before boot registry",
+		"before boot a",
+		"after boot a",
+		"after boot registry",
+		"before run registry",
+		"before run run",
+		"after run run",
+		"after run registry",
+		"before shutdown registry",
+		"before shutdown a",
+		"after shutdown a",
+		"after shutdown registry",
+	}, h.events)
+}
+
+func TestRegistryAndServiceHooks_Filter(t *testing.T) {
+	var registry, service []string
+	r := New(
+		WithRegistryHook(PhaseShutdown, StageBefore, func(context.Context, HookEvent) error {
+			registry = append(registry, "unready")
+			return nil
+		}),
+		WithServiceHook(PhaseBoot, StageAfter, func(_ context.Context, e HookEvent) error {
+			service = append(service, e.Service.Name)
+			return nil
+		}),
+	)
+	r.Register("a", &svc{name: "a", rec: &recorder{}})
+	r.Register("plain", &struct{}{})
+
+	require.NoError(t, r.Boot())
+	require.NoError(t, r.Shutdown(context.Background()))
+
+	assert.Equal(t, []string{"unready"}, registry)
+	assert.Equal(t, []string{"a"}, service) // only Bootable services.
+}
+
+func TestHooks_BootHookErrorFailsBoot(t *testing.T) {
+	hookErr := errors.New("metrics are down")
+	rec := &recorder{}
+	r := New(WithServiceHook(PhaseBoot, StageBefore, func(context.Context, HookEvent) error {
+		return hookErr
+	}))
+	r.Register("a", &svc{name: "a", rec: rec})
+
+	err := r.Boot()
+	assert.ErrorIs(t, err, hookErr)
+	var serr *ServiceError
+	require.ErrorAs(t, err, &serr)
+	assert.Equal(t, "a", serr.Service)
+	assert.Empty(t, rec.booted)
+	assert.Equal(t, hexa.StateFailed, r.Statuses()[0].State)
+}
+
+func TestHooks_ShutdownHookErrorsAreAggregated(t *testing.T) {
+	serviceErr := errors.New("service hook")
+	registryErr := errors.New("registry hook")
+	rec := &recorder{}
+	r := New(
+		WithServiceHook(PhaseShutdown, StageAfter, func(context.Context, HookEvent) error { return serviceErr }),
+		WithRegistryHook(PhaseShutdown, StageAfter, func(_ context.Context, e HookEvent) error {
+			assert.ErrorIs(t, e.Err, serviceErr) // after hooks see the phase's error.
+			return registryErr
+		}),
+	)
+	r.Register("a", &svc{name: "a", rec: rec})
+
+	err := r.Shutdown(context.Background())
+	assert.ErrorIs(t, err, serviceErr)
+	assert.ErrorIs(t, err, registryErr)
+	assert.Equal(t, []string{"a"}, rec.shutdown)
+}
@@ -0,0 +1,131 @@
+package sr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/kamva/hexa"
+	"github.com/kamva/hexa/hlog"
+	"github.com/kamva/tracer"
+)
+
+// boot boots the service and retries it according to its boot retry policy.
+func (r *serviceRegistry) boot(d *hexa.Descriptor, bootable hexa.Bootable) error {
+	log := hlog.With(hlog.String("name", d.Name), hlog.Int("priority", d.Priority))
+	p := d.BootRetry
+
+	r.st.set(d.Name, hexa.StateBooting, nil)
+	for retries := 0; ; retries++ {
+		log.Debug("boot service", hlog.Int("retry", retries))
+		err := r.bootWithTimeout(d, bootable)
+		if err == nil {
+			r.st.set(d.Name, hexa.StateBooted, nil)
+			return nil
+		}
+
+		if errors.Is(err, ErrBootTimeout) || retries >= p.MaxRetries {
+			log.Error("service boot failed", hlog.Err(err))
+			r.st.set(d.Name, hexa.StateFailed, err)
+			return tracer.Trace(&ServiceError{Service: d.Name, Err: err})
+		}
+
+		delay := backoff(p.Backoff, p.MaxBackoff, retries)
+		log.Warn("service boot failed, retry it", hlog.Err(err), hlog.Duration("backoff", delay))
+		select {
+		case <-r.stopCh:
+			r.st.set(d.Name, hexa.StateFailed, err)
+			return tracer.Trace(&ServiceError{Service: d.Name, Err: err})
+		case <-time.After(delay):
+		}
+	}
+}
+
+// bootWithTimeout boots the service and returns ErrBootTimeout if the boot
+// doesn't return in time. ContextBootable services get a context which is
+// canceled on the timeout. The Bootable interface doesn't get any context,
+// so a timed-out boot keeps running and the shutdown waits for it.
+func (r *serviceRegistry) bootWithTimeout(d *hexa.Descriptor, bootable hexa.Bootable) error {
+	timeout := d.BootTimeout
+	if cb, ok := bootable.(hexa.ContextBootable); ok {
+		ctx := context.Background()
+		if timeout != 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		err := cb.BootContext(ctx)
+		if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return fmt.Errorf("%w after %s: %v", ErrBootTimeout, timeout, err)
+		}
+		return err
+	}
+
+	if timeout == 0 {
+		return bootable.Boot()
+	}
+
+	errCh := make(chan error, 1)
+	r.boots.Add(1)
+	go func() {
+		defer r.boots.Done()
+		errCh <- bootable.Boot()
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-time.After(timeout):
+		return fmt.Errorf("%w after %s", ErrBootTimeout, timeout)
+	}
+}
+
+// waitForBoots waits for timed-out boots which are still running, so we
+// don't shut down services while they're booting.
+func (r *serviceRegistry) waitForBoots(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		r.boots.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return tracer.Trace(ctx.Err())
+	}
+}
+
+// shutdownService shuts down the service within its timeout. it doesn't
+// wait for the service if the service ignores the context.
+func (r *serviceRegistry) shutdownService(ctx context.Context, d *hexa.Descriptor, shutdownable hexa.Shutdownable) *ServiceError {
+	log := hlog.With(hlog.String("name", d.Name), hlog.Int("priority", d.Priority))
+	if d.ShutdownTimeout != 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, d.ShutdownTimeout)
+		defer cancel()
+	}
+
+	log.Debug("shutdown service")
+	r.st.set(d.Name, hexa.StateStopping, nil)
+	errCh := make(chan error, 1)
+	go func() { errCh <- shutdownable.Shutdown(ctx) }()
+
+	var err error
+	select {
+	case err = <-errCh:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	if err != nil {
+		log.Error("failed service shutdown", hlog.Err(err))
+		r.st.set(d.Name, hexa.StateFailed, err)
+		return &ServiceError{Service: d.Name, Err: err}
+	}
+
+	r.st.set(d.Name, hexa.StateStopped, nil)
+	return nil
+}
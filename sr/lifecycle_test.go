@@ -0,0 +1,172 @@
+package sr
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kamva/hexa"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// flakySvc fails to boot until its boot is called `failures` times.
+type flakySvc struct {
+	failures int
+	boots    int
+}
+
+func (s *flakySvc) Boot() error {
+	s.boots++
+	if s.boots <= s.failures {
+		return errors.New("dependency is not ready")
+	}
+	return nil
+}
+
+type slowSvc struct {
+	delay time.Duration
+}
+
+func (s *slowSvc) Boot() error {
+	time.Sleep(s.delay)
+	return nil
+}
+
+// Shutdown ignores the context deliberately.
+func (s *slowSvc) Shutdown(context.Context) error {
+	time.Sleep(s.delay)
+	return nil
+}
+
+type failingShutdownSvc struct {
+	err error
+}
+
+func (s *failingShutdownSvc) Shutdown(context.Context) error { return s.err }
+
+func TestBoot_RetriesFailedBoot(t *testing.T) {
+	s := &flakySvc{failures: 2}
+	r := New()
+	r.RegisterByDescriptor(&hexa.Descriptor{
+		Name:      "s",
+		Instance:  s,
+		BootRetry: hexa.RetryPolicy{MaxRetries: 2, Backoff: time.Millisecond},
+	})
+
+	require.NoError(t, r.Boot())
+	assert.Equal(t, 3, s.boots)
+}
+
+func TestBoot_RetriesExhausted(t *testing.T) {
+	s := &flakySvc{failures: 5}
+	r := New()
+	r.RegisterByDescriptor(&hexa.Descriptor{
+		Name:      "s",
+		Instance:  s,
+		BootRetry: hexa.RetryPolicy{MaxRetries: 1, Backoff: time.Millisecond},
+	})
+
+	err := r.Boot()
+	var serr *ServiceError
+	require.ErrorAs(t, err, &serr)
+	assert.Equal(t, "s", serr.Service)
+	assert.Equal(t, 2, s.boots)
+}
+
+func TestBoot_Timeout(t *testing.T) {
+	r := New()
+	r.RegisterByDescriptor(&hexa.Descriptor{
+		Name:        "s",
+		Instance:    &slowSvc{delay: time.Second},
+		BootTimeout: 10 * time.Millisecond,
+		BootRetry:   hexa.RetryPolicy{MaxRetries: 3},
+	})
+
+	start := time.Now()
+	assert.ErrorIs(t, r.Boot(), ErrBootTimeout)
+	assert.Less(t, time.Since(start), time.Second) // a timed-out boot is not retried.
+}
+
+// ctxBootSvc boots until its context is canceled.
+type ctxBootSvc struct {
+	canceled bool
+}
+
+func (s *ctxBootSvc) Boot() error {
+	return s.BootContext(context.Background())
+}
+
+func (s *ctxBootSvc) BootContext(ctx context.Context) error {
+	<-ctx.Done()
+	s.canceled = true
+	return ctx.Err()
+}
+
+func TestBoot_TimeoutCancelsContextBootable(t *testing.T) {
+	s := &ctxBootSvc{}
+	r := New()
+	r.RegisterByDescriptor(&hexa.Descriptor{Name: "s", Instance: s, BootTimeout: 10 * time.Millisecond})
+
+	assert.ErrorIs(t, r.Boot(), ErrBootTimeout)
+	assert.True(t, s.canceled)
+}
+
+func TestShutdown_WaitsForTimedOutBoot(t *testing.T) {
+	r := New()
+	r.RegisterByDescriptor(&hexa.Descriptor{
+		Name:        "s",
+		Instance:    &slowSvc{delay: 100 * time.Millisecond},
+		BootTimeout: 10 * time.Millisecond,
+	})
+
+	start := time.Now()
+	assert.ErrorIs(t, r.Boot(), ErrBootTimeout)
+	require.NoError(t, r.Shutdown(context.Background()))
+	// the shutdown waits for the boot and then shuts down slowSvc too.
+	assert.GreaterOrEqual(t, time.Since(start), 200*time.Millisecond)
+}
+
+func TestShutdown_AggregatesErrors(t *testing.T) {
+	errA := errors.New("a failed")
+	errB := errors.New("b failed")
+	rec := &recorder{}
+	r := New()
+	r.Register("a", &failingShutdownSvc{err: errA})
+	r.Register("ok", &svc{name: "ok", rec: rec})
+	r.Register("b", &failingShutdownSvc{err: errB})
+
+	err := r.Shutdown(context.Background())
+	require.Error(t, err)
+	assert.ErrorIs(t, err, errA)
+	assert.ErrorIs(t, err, errB)
+	assert.Contains(t, err.Error(), "service a: a failed")
+	assert.Contains(t, err.Error(), "service b: b failed")
+	assert.Equal(t, []string{"ok"}, rec.shutdown)
+
+	var serr *ServiceError
+	require.ErrorAs(t, err, &serr)
+	assert.Equal(t, "b", serr.Service) // b shuts down first.
+
+	// Next calls return the same result.
+	assert.ErrorIs(t, r.Shutdown(context.Background()), errA)
+}
+
+func TestShutdown_ServiceTimeout(t *testing.T) {
+	rec := &recorder{}
+	r := New()
+	r.Register("ok", &svc{name: "ok", rec: rec})
+	r.RegisterByDescriptor(&hexa.Descriptor{
+		Name:            "slow",
+		Instance:        &slowSvc{delay: time.Second},
+		ShutdownTimeout: 10 * time.Millisecond,
+	})
+
+	start := time.Now()
+	err := r.Shutdown(context.Background())
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Contains(t, err.Error(), "service slow")
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Equal(t, []string{"ok"}, rec.shutdown)
+}
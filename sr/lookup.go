@@ -0,0 +1,59 @@
+package sr
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/kamva/hexa"
+	"github.com/kamva/tracer"
+)
+
+var (
+	ErrServiceNotFound  = errors.New("service not found")
+	ErrAmbiguousService = errors.New("more than one service found")
+)
+
+// Get returns the service with the provided name as T. It returns
+// an error if the service doesn't exist or its type is not T.
+func Get[T any](r hexa.ServiceRegistry, name string) (T, error) {
+	var zero T
+	s := r.Service(name)
+	if s == nil {
+		return zero, tracer.Trace(fmt.Errorf("%w: %s", ErrServiceNotFound, name))
+	}
+
+	t, ok := s.(T)
+	if !ok {
+		return zero, tracer.Trace(fmt.Errorf("service %s with type %T is not %s", name, s, typeName[T]()))
+	}
+	return t, nil
+}
+
+// Find returns the single service which implements T. It returns
+// an error if no service or more than one service implements T.
+func Find[T any](r hexa.ServiceRegistry) (T, error) {
+	var zero T
+	var found T
+	var names []string
+	for _, d := range r.Descriptors() {
+		if t, ok := d.Instance.(T); ok {
+			found = t
+			names = append(names, d.Name)
+		}
+	}
+
+	switch len(names) {
+	case 0:
+		return zero, tracer.Trace(fmt.Errorf("%w: no service implements %s", ErrServiceNotFound, typeName[T]()))
+	case 1:
+		return found, nil
+	default:
+		return zero, tracer.Trace(fmt.Errorf("%w: services %s implement %s", ErrAmbiguousService, strings.Join(names, ", "), typeName[T]()))
+	}
+}
+
+func typeName[T any]() string {
+	return reflect.TypeOf((*T)(nil)).Elem().String()
+}
@@ -0,0 +1,47 @@
+package sr
+
+import (
+	"testing"
+
+	"github.com/kamva/hexa"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGet(t *testing.T) {
+	r := New()
+	r.Register("a", &svc{name: "a"})
+
+	s, err := Get[*svc](r, "a")
+	require.NoError(t, err)
+	assert.Equal(t, "a", s.name)
+
+	b, err := Get[hexa.Bootable](r, "a")
+	require.NoError(t, err)
+	assert.NotNil(t, b)
+
+	_, err = Get[*svc](r, "missing")
+	assert.ErrorIs(t, err, ErrServiceNotFound)
+
+	_, err = Get[hexa.Runnable](r, "a")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "hexa.Runnable")
+}
+
+func TestFind(t *testing.T) {
+	r := New()
+	r.Register("a", &svc{name: "a"})
+	r.Register("run", &runSvc{})
+
+	s, err := Find[hexa.Runnable](r)
+	require.NoError(t, err)
+	assert.IsType(t, &runSvc{}, s)
+
+	_, err = Find[hexa.Health](r)
+	assert.ErrorIs(t, err, ErrServiceNotFound)
+
+	r.Register("b", &svc{name: "b"})
+	_, err = Find[hexa.Bootable](r)
+	assert.ErrorIs(t, err, ErrAmbiguousService)
+	assert.Contains(t, err.Error(), "a, b")
+}
@@ -18,6 +18,17 @@ func (m *multiSearchRegistry) Descriptors() []*hexa.Descriptor {
 	return l
 }
 
+func (m *multiSearchRegistry) Statuses() []hexa.ServiceStatus {
+	l := make([]hexa.ServiceStatus, 0)
+	for i := len(m.search) - 1; i >= 0; i-- { // get statuses from registries in revert order, just like descriptors.
+		if st, ok := m.search[i].(hexa.ServiceStatusReporter); ok {
+			l = append(l, st.Statuses()...)
+		}
+	}
+
+	return l
+}
+
 func (m *multiSearchRegistry) Descriptor(name string) *hexa.Descriptor {
 	for _, r := range m.search {
 		if d := r.Descriptor(name); d != nil {
@@ -44,3 +55,4 @@ func NewMultiSearchRegistry(primary hexa.ServiceRegistry, search ...hexa.Service
 }
 
 var _ hexa.ServiceRegistry = &multiSearchRegistry{}
+var _ hexa.ServiceStatusReporter = &multiSearchRegistry{}
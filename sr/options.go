@@ -0,0 +1,45 @@
+package sr
+
+import "time"
+
+const (
+	// DefaultShutdownTimeout is the timeout of the shutdown which the
+	// registry triggers when a service fails.
+	DefaultShutdownTimeout = 30 * time.Second
+	// DefaultBackoff is the delay before the first restart or boot retry
+	// of a service when its policy doesn't specify it.
+	DefaultBackoff = time.Second
+)
+
+// Option configures the service registry.
+type Option func(o *options)
+
+type options struct {
+	shutdownTimeout time.Duration
+	parallel        bool
+	hooks           []Hook
+}
+
+func defaultOptions() options {
+	return options{
+		shutdownTimeout: DefaultShutdownTimeout,
+	}
+}
+
+// WithShutdownTimeout sets timeout of the shutdown which the registry
+// triggers when a service fails.
+func WithShutdownTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.shutdownTimeout = timeout
+	}
+}
+
+// WithParallelLifecycle boots and shuts down independent services in
+// parallel. Without it, the registry boots services one by one in their
+// dependency order. Please note in parallel mode the priority doesn't
+// specify the order of services, just their dependencies do.
+func WithParallelLifecycle() Option {
+	return func(o *options) {
+		o.parallel = true
+	}
+}
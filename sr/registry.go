@@ -18,15 +18,40 @@ import (
 type serviceRegistry struct {
 	mu sync.RWMutex
 	l  []*hexa.Descriptor
+	o  options
+	st *states
 
-	booted     uint32 // is 1 if you boot services.
-	done       uint32 // is 1 if you shutdown services.
+	booted     uint32        // is 1 if you boot services.
+	running    uint32        // is 1 if you run services.
+	done       uint32        // is 1 if you shutdown services.
+	stopCh     chan struct{} // is closed when shutdown begins.
 	shutdownCh chan struct{}
+	boots      sync.WaitGroup // timed-out boots which are still running.
+
+	errMu       sync.Mutex
+	err         error // the first fatal error.
+	shutdownErr error
+}
+
+// Registry is the service registry which also runs its services and
+// tracks their lifecycle state.
+type Registry interface {
+	hexa.ServiceRegistry
+	hexa.ServiceRunner
+	hexa.ServiceStatusReporter
 }
 
-func New() hexa.ServiceRegistry {
+func New(opts ...Option) Registry {
+	o := defaultOptions()
+	for _, opt := range opts {
+		opt(&o)
+	}
+
 	return &serviceRegistry{
 		l:          make([]*hexa.Descriptor, 0),
+		o:          o,
+		st:         newStates(),
+		stopCh:     make(chan struct{}),
 		shutdownCh: make(chan struct{}),
 	}
 }
@@ -49,8 +74,9 @@ func (r *serviceRegistry) RegisterByDescriptor(d *hexa.Descriptor) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	if r.descriptor(d.Name) != nil {
+	if old := r.descriptor(d.Name); old != nil {
 		hlog.Warn("you are overwriting service in service registry", hlog.String("name", d.Name))
+		r.remove(old)
 	}
 
 	if _, ok := d.Instance.(hexa.Bootable); ok && atomic.LoadUint32(&r.booted) == 1 {
@@ -69,6 +95,7 @@ func (r *serviceRegistry) RegisterByDescriptor(d *hexa.Descriptor) {
 	}
 
 	r.l = append(r.l, d)
+	r.st.set(d.Name, hexa.StateRegistered, nil)
 
 	// Sort the list by priority.
 	sort.Slice(r.l, func(i, j int) bool { return r.l[i].Priority < r.l[j].Priority })
@@ -80,42 +107,32 @@ func (r *serviceRegistry) Boot() error {
 		return nil
 	}
 
-	for _, d := range r.Descriptors() {
-		bootable, ok := d.Instance.(hexa.Bootable)
-		if !ok {
-			continue
-		}
-		log := hlog.With(hlog.String("name", d.Name), hlog.Int("priority", d.Priority))
-
-		log.Debug("boot service")
-		if err := bootable.Boot(); err != nil {
-			log.Error("service boot failed")
+	ctx := context.Background()
+	return r.withHooks(ctx, PhaseBoot, nil, func() error {
+		g, err := newGraph(r.Descriptors())
+		if err != nil {
 			return tracer.Trace(err)
 		}
-	}
 
-	return nil
+		return r.lifecycle(g.order, g.deps, func(d *hexa.Descriptor) error {
+			bootable, ok := d.Instance.(hexa.Bootable)
+			if !ok {
+				r.st.set(d.Name, hexa.StateBooted, nil)
+				return nil
+			}
+			return r.withHooks(ctx, PhaseBoot, d, func() error { return r.boot(d, bootable) })
+		})
+	})
 }
 
 func (r *serviceRegistry) Shutdown(ctx context.Context) error {
 	if atomic.CompareAndSwapUint32(&r.done, 0, 1) { // if its the first time you want to shutdown services:
+		close(r.stopCh)
 		go func() {
-			dl := r.Descriptors()
-			// sort descending.
-			sort.Slice(dl, func(i int, j int) bool { return dl[i].Priority > dl[j].Priority })
-			for _, d := range dl {
-				shutdownable, ok := d.Instance.(hexa.Shutdownable)
-				if !ok {
-					continue
-				}
-
-				log := hlog.With(hlog.String("name", d.Name), hlog.Int("priority", d.Priority))
-				log.Debug("shutdown service")
-				if err := shutdownable.Shutdown(ctx); err != nil {
-					log.Error("failed service shutdown")
-				}
-			}
-
+			err := r.shutdown(ctx)
+			r.errMu.Lock()
+			r.shutdownErr = err
+			r.errMu.Unlock()
 			close(r.shutdownCh)
 		}()
 	} else {
@@ -128,8 +145,81 @@ func (r *serviceRegistry) Shutdown(ctx context.Context) error {
 		return tracer.Trace(ctx.Err())
 	case <-r.shutdownCh:
 		hlog.Info("app shutdown.")
-		return nil
+		r.errMu.Lock()
+		defer r.errMu.Unlock()
+		return r.shutdownErr
+	}
+}
+
+// shutdown shuts down all services and returns their errors and
+// the shutdown hooks errors as a ShutdownError. Unlike other phases,
+// it doesn't stop on hooks errors, because it must shut down services.
+func (r *serviceRegistry) shutdown(ctx context.Context) error {
+	var mu sync.Mutex
+	var errs []error
+	collect := func(err error) {
+		if err == nil {
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		errs = append(errs, err)
+	}
+	shutdownErr := func() error {
+		mu.Lock()
+		defer mu.Unlock()
+		if len(errs) == 0 {
+			return nil
+		}
+		return &ShutdownError{Errors: append([]error(nil), errs...)}
+	}
+
+	shutdown := func(d *hexa.Descriptor) error {
+		shutdownable, ok := d.Instance.(hexa.Shutdownable)
+		if !ok {
+			r.st.set(d.Name, hexa.StateStopped, nil)
+			return nil
+		}
+
+		collect(r.runHooks(ctx, HookEvent{Phase: PhaseShutdown, Stage: StageBefore, Service: d}))
+		var err error
+		if serr := r.shutdownService(ctx, d, shutdownable); serr != nil {
+			err = serr
+			collect(err)
+		}
+		collect(r.runHooks(ctx, HookEvent{Phase: PhaseShutdown, Stage: StageAfter, Service: d, Err: err}))
+		return nil // continue to shut down other services.
+	}
+
+	collect(r.runHooks(ctx, HookEvent{Phase: PhaseShutdown, Stage: StageBefore}))
+
+	if err := r.waitForBoots(ctx); err != nil {
+		hlog.Error("timed-out boots of services are still running", hlog.Err(err))
+		collect(err)
+	}
+
+	g, err := newGraph(r.Descriptors())
+	if err != nil {
+		collect(tracer.Trace(err))
+	} else {
+		_ = r.lifecycle(g.reverse(), g.dependents, shutdown)
 	}
+
+	collect(r.runHooks(ctx, HookEvent{Phase: PhaseShutdown, Stage: StageAfter, Err: shutdownErr()}))
+
+	if err := shutdownErr(); err != nil {
+		return tracer.Trace(err)
+	}
+	return nil
+}
+
+// lifecycle runs fn for services either in parallel or one by one
+// according to the registry's options.
+func (r *serviceRegistry) lifecycle(ds []*hexa.Descriptor, waitFor map[*hexa.Descriptor][]*hexa.Descriptor, fn func(d *hexa.Descriptor) error) error {
+	if r.o.parallel {
+		return runInParallel(ds, waitFor, fn)
+	}
+	return runInOrder(ds, fn)
 }
 
 func (r *serviceRegistry) ShutdownCh() (shutdownCh chan struct{}) {
@@ -152,6 +242,17 @@ func (r *serviceRegistry) Descriptor(name string) *hexa.Descriptor {
 	return r.descriptor(name)
 }
 
+// remove removes the descriptor without locking; callers must hold
+// the write lock.
+func (r *serviceRegistry) remove(d *hexa.Descriptor) {
+	for i, v := range r.l {
+		if v == d {
+			r.l = append(r.l[:i:i], r.l[i+1:]...)
+			return
+		}
+	}
+}
+
 // descriptor looks up a descriptor by name without locking; callers must hold
 // at least the read lock.
 func (r *serviceRegistry) descriptor(name string) *hexa.Descriptor {
@@ -173,4 +274,8 @@ func (r *serviceRegistry) Service(name string) hexa.Service {
 	return nil
 }
 
-var _ hexa.ServiceRegistry = &serviceRegistry{}
+func (r *serviceRegistry) Statuses() []hexa.ServiceStatus {
+	return r.st.statuses(r.Descriptors())
+}
+
+var _ Registry = &serviceRegistry{}
@@ -175,4 +175,16 @@ func TestMultiSearchRegistry_SearchesAllRegistries(t *testing.T) {
 	// registry used to be dropped by an off-by-one).
 	got := names(multi.Descriptors())
 	assert.ElementsMatch(t, []string{"one", "two"}, got)
+	assert.Len(t, multi.(hexa.ServiceStatusReporter).Statuses(), 2)
+}
+
+func TestRegisterByDescriptor_Overwrites(t *testing.T) {
+	r := New()
+	first := &healthSvc{id: "first"}
+	second := &healthSvc{id: "second"}
+	r.Register("a", first)
+	r.Register("a", second)
+
+	assert.Len(t, r.Descriptors(), 1)
+	assert.Equal(t, second, r.Service("a"))
 }
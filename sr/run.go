@@ -0,0 +1,172 @@
+package sr
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/kamva/hexa"
+	"github.com/kamva/hexa/hlog"
+	"github.com/kamva/tracer"
+)
+
+// Run runs services in their boot order.
+func (r *serviceRegistry) Run() error {
+	if !atomic.CompareAndSwapUint32(&r.running, 0, 1) {
+		hlog.Warn("skip service registry run, it has been ran already!")
+		return nil
+	}
+
+	return r.withHooks(context.Background(), PhaseRun, nil, func() error {
+		g, err := newGraph(r.Descriptors())
+		if err != nil {
+			return tracer.Trace(err)
+		}
+
+		// Run is non-blocking, so we don't need to run services in parallel.
+		return runInOrder(g.order, func(d *hexa.Descriptor) error {
+			runnable, ok := d.Instance.(hexa.Runnable)
+			if !ok {
+				return nil
+			}
+
+			done, err := r.run(d, runnable)
+			if err != nil {
+				return tracer.Trace(err)
+			}
+
+			go r.supervise(d, runnable, done)
+			return nil
+		})
+	})
+}
+
+// run runs the service between its run hooks.
+func (r *serviceRegistry) run(d *hexa.Descriptor, runnable hexa.Runnable) (<-chan error, error) {
+	log := hlog.With(hlog.String("name", d.Name), hlog.Int("priority", d.Priority))
+
+	var done <-chan error
+	err := r.withHooks(context.Background(), PhaseRun, d, func() error {
+		log.Debug("run service")
+		var err error
+		if done, err = runnable.Run(); err != nil {
+			log.Error("service run failed", hlog.Err(err))
+			r.st.set(d.Name, hexa.StateFailed, err)
+			return err
+		}
+
+		r.st.set(d.Name, hexa.StateRunning, nil)
+		return nil
+	})
+	return done, err
+}
+
+// supervise waits for the service's run to be done and restarts
+// it according to its restart policy. if the service can not be
+// restarted and its run is done with an error, it shutdowns the
+// registry.
+func (r *serviceRegistry) supervise(d *hexa.Descriptor, runnable hexa.Runnable, done <-chan error) {
+	log := hlog.With(hlog.String("name", d.Name))
+	p := d.RestartPolicy
+
+	for restarts := 0; ; restarts++ {
+		err := <-done // err is nil if the channel is closed without any error.
+		if r.stopping() {
+			return
+		}
+
+		if err != nil {
+			log.Error("service run is done with error", hlog.Err(err), hlog.ErrStack(err))
+			r.st.set(d.Name, hexa.StateFailed, err)
+		} else {
+			log.Info("service run is done")
+			r.st.set(d.Name, hexa.StateStopped, nil)
+		}
+
+		if !shouldRestart(p, err, restarts) {
+			if err != nil {
+				r.fail(tracer.Trace(&ServiceError{Service: d.Name, Err: err}))
+			}
+			return
+		}
+
+		delay := backoff(p.Backoff, p.MaxBackoff, restarts)
+		log.Info("restart service", hlog.Int("restart", restarts+1), hlog.Duration("backoff", delay))
+		select {
+		case <-r.stopCh:
+			return
+		case <-time.After(delay):
+		}
+
+		if done, err = r.run(d, runnable); err != nil {
+			done = errChan(err)
+		}
+	}
+}
+
+func (r *serviceRegistry) stopping() bool {
+	return atomic.LoadUint32(&r.done) == 1
+}
+
+// fail keeps the first fatal error and shutdowns the registry.
+func (r *serviceRegistry) fail(err error) {
+	r.errMu.Lock()
+	if r.err == nil {
+		r.err = err
+	}
+	r.errMu.Unlock()
+
+	go func() {
+		if err := ShutdownWithTimeout(r, r.o.shutdownTimeout); err != nil {
+			hlog.Error("shutdown after service failure failed", hlog.Err(err))
+		}
+	}()
+}
+
+func (r *serviceRegistry) Wait() error {
+	<-r.shutdownCh
+
+	r.errMu.Lock()
+	defer r.errMu.Unlock()
+	return r.err
+}
+
+func shouldRestart(p hexa.RestartPolicy, err error, restarts int) bool {
+	if p.MaxRestarts != 0 && restarts >= p.MaxRestarts {
+		return false
+	}
+
+	switch p.Mode {
+	case hexa.RestartAlways:
+		return true
+	case hexa.RestartOnFailure:
+		return err != nil
+	default:
+		return false
+	}
+}
+
+// backoff returns the delay before the nth retry. the delay doubles
+// on each retry and is capped by maxDelay (if it's not zero).
+func backoff(initial time.Duration, maxDelay time.Duration, n int) time.Duration {
+	delay := initial
+	if delay == 0 {
+		delay = DefaultBackoff
+	}
+
+	for i := 0; i < n && (maxDelay == 0 || delay < maxDelay); i++ {
+		delay *= 2
+	}
+
+	if maxDelay != 0 && delay > maxDelay {
+		return maxDelay
+	}
+	return delay
+}
+
+func errChan(err error) <-chan error {
+	ch := make(chan error, 1)
+	ch <- err
+	close(ch)
+	return ch
+}
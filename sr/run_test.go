@@ -0,0 +1,163 @@
+package sr
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kamva/hexa"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runSvc is a fake Runnable service; each run returns a new done channel
+// which the test can finish using the finish method.
+type runSvc struct {
+	mu     sync.Mutex
+	runs   int
+	runErr error
+	done   chan error
+}
+
+func (s *runSvc) Run() (<-chan error, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.runErr != nil {
+		return nil, s.runErr
+	}
+	s.runs++
+	s.done = make(chan error, 1)
+	return s.done, nil
+}
+
+func (s *runSvc) finish(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err != nil {
+		s.done <- err
+	}
+	close(s.done)
+}
+
+func (s *runSvc) runCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.runs
+}
+
+func waitErr(t *testing.T, r Registry) error {
+	t.Helper()
+	ch := make(chan error, 1)
+	go func() { ch <- r.Wait() }()
+
+	select {
+	case err := <-ch:
+		return err
+	case <-time.After(3 * time.Second):
+		t.Fatal("registry did not shut down")
+		return nil
+	}
+}
+
+func TestRun_RunsRunnables(t *testing.T) {
+	s := &runSvc{}
+	r := New()
+	r.Register("s", s)
+	r.Register("plain", &struct{}{})
+
+	require.NoError(t, r.Run())
+	assert.Equal(t, 1, s.runCount())
+
+	// Run is idempotent.
+	require.NoError(t, r.Run())
+	assert.Equal(t, 1, s.runCount())
+
+	require.NoError(t, r.Shutdown(context.Background()))
+	assert.NoError(t, waitErr(t, r))
+}
+
+func TestRun_ReturnsRunError(t *testing.T) {
+	runErr := errors.New("can not listen")
+	r := New()
+	r.Register("s", &runSvc{runErr: runErr})
+
+	assert.ErrorIs(t, r.Run(), runErr)
+}
+
+func TestRun_FailureShutsDownRegistry(t *testing.T) {
+	rec := &recorder{}
+	s := &runSvc{}
+	r := New()
+	r.Register("other", &svc{name: "other", rec: rec})
+	r.Register("s", s)
+
+	require.NoError(t, r.Run())
+	runErr := errors.New("crashed")
+	s.finish(runErr)
+
+	err := waitErr(t, r)
+	assert.ErrorIs(t, err, runErr)
+	assert.Contains(t, err.Error(), "service s")
+	assert.Equal(t, []string{"other"}, rec.shutdown)
+}
+
+func TestRun_RestartOnFailure(t *testing.T) {
+	s := &runSvc{}
+	r := New()
+	r.RegisterByDescriptor(&hexa.Descriptor{
+		Name:     "s",
+		Instance: s,
+		RestartPolicy: hexa.RestartPolicy{
+			Mode:        hexa.RestartOnFailure,
+			MaxRestarts: 1,
+			Backoff:     time.Millisecond,
+		},
+	})
+
+	require.NoError(t, r.Run())
+	s.finish(errors.New("crashed"))
+	require.Eventually(t, func() bool { return s.runCount() == 2 }, time.Second, time.Millisecond)
+
+	// Restarts are exhausted, so the next failure is fatal.
+	runErr := errors.New("crashed again")
+	s.finish(runErr)
+	assert.ErrorIs(t, waitErr(t, r), runErr)
+}
+
+func TestRun_ServiceDoneWithoutErrorIsNotFatal(t *testing.T) {
+	s := &runSvc{}
+	r := New()
+	r.Register("s", s)
+
+	require.NoError(t, r.Run())
+	s.finish(nil)
+
+	select {
+	case <-r.ShutdownCh():
+		t.Fatal("registry must not shut down when a service is done without error")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestShouldRestart(t *testing.T) {
+	crash := errors.New("crash")
+
+	assert.False(t, shouldRestart(hexa.RestartPolicy{}, crash, 0))
+	assert.False(t, shouldRestart(hexa.RestartPolicy{Mode: hexa.RestartNever}, crash, 0))
+	assert.True(t, shouldRestart(hexa.RestartPolicy{Mode: hexa.RestartOnFailure}, crash, 0))
+	assert.False(t, shouldRestart(hexa.RestartPolicy{Mode: hexa.RestartOnFailure}, nil, 0))
+	assert.True(t, shouldRestart(hexa.RestartPolicy{Mode: hexa.RestartAlways}, nil, 0))
+	assert.False(t, shouldRestart(hexa.RestartPolicy{Mode: hexa.RestartAlways, MaxRestarts: 2}, nil, 2))
+}
+
+func TestBackoff(t *testing.T) {
+	assert.Equal(t, time.Second, backoff(time.Second, 5*time.Second, 0))
+	assert.Equal(t, 2*time.Second, backoff(time.Second, 5*time.Second, 1))
+	assert.Equal(t, 4*time.Second, backoff(time.Second, 5*time.Second, 2))
+	assert.Equal(t, 5*time.Second, backoff(time.Second, 5*time.Second, 3))
+	assert.Equal(t, 8*time.Second, backoff(time.Second, 0, 3))
+
+	assert.Equal(t, DefaultBackoff, backoff(0, 0, 0))
+}
@@ -0,0 +1,61 @@
+package sr
+
+import (
+	"sync"
+	"time"
+
+	"github.com/kamva/hexa"
+)
+
+// states keeps lifecycle state of services.
+type states struct {
+	mu sync.RWMutex
+	m  map[string]*hexa.ServiceStatus
+}
+
+func newStates() *states {
+	return &states{m: make(map[string]*hexa.ServiceStatus)}
+}
+
+// set sets the service's state. the error is just kept for the failed state.
+func (s *states) set(name string, state hexa.ServiceState, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	st, ok := s.m[name]
+	if !ok || state == hexa.StateRegistered { // registering a service again resets its state.
+		st = &hexa.ServiceStatus{Name: name, Timestamps: make(map[hexa.ServiceState]time.Time)}
+		s.m[name] = st
+	}
+
+	st.State = state
+	st.Since = now
+	st.Timestamps[state] = now
+	st.Error = ""
+	if state == hexa.StateFailed && err != nil {
+		st.Error = err.Error()
+	}
+}
+
+// statuses returns a copy of the descriptors' statuses.
+func (s *states) statuses(ds []*hexa.Descriptor) []hexa.ServiceStatus {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	l := make([]hexa.ServiceStatus, 0, len(ds))
+	for _, d := range ds {
+		st, ok := s.m[d.Name]
+		if !ok {
+			continue
+		}
+
+		cp := *st
+		cp.Timestamps = make(map[hexa.ServiceState]time.Time, len(st.Timestamps))
+		for k, v := range st.Timestamps {
+			cp.Timestamps[k] = v
+		}
+		l = append(l, cp)
+	}
+	return l
+}
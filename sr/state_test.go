@@ -0,0 +1,65 @@
+package sr
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kamva/hexa"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func stateMap(r Registry) map[string]hexa.ServiceState {
+	m := make(map[string]hexa.ServiceState)
+	for _, st := range r.Statuses() {
+		m[st.Name] = st.State
+	}
+	return m
+}
+
+func TestStatuses_FollowLifecycle(t *testing.T) {
+	rec := &recorder{}
+	s := &runSvc{}
+	r := New()
+	r.Register("boot", &svc{name: "boot", rec: rec})
+	r.Register("run", s)
+	r.Register("broken", &svc{name: "broken", rec: rec, bootErr: errors.New("boom")})
+
+	assert.Equal(t, map[string]hexa.ServiceState{
+		"boot":   hexa.StateRegistered,
+		"run":    hexa.StateRegistered,
+		"broken": hexa.StateRegistered,
+	}, stateMap(r))
+
+	require.Error(t, r.Boot())
+	assert.Equal(t, hexa.StateBooted, stateMap(r)["boot"])
+	assert.Equal(t, hexa.StateBooted, stateMap(r)["run"])
+	assert.Equal(t, hexa.StateFailed, stateMap(r)["broken"])
+
+	require.NoError(t, r.Run())
+	assert.Equal(t, hexa.StateRunning, stateMap(r)["run"])
+
+	require.NoError(t, r.Shutdown(context.Background()))
+	assert.Equal(t, map[string]hexa.ServiceState{
+		"boot":   hexa.StateStopped,
+		"run":    hexa.StateStopped,
+		"broken": hexa.StateStopped,
+	}, stateMap(r))
+
+	st := r.Statuses()[0]
+	assert.Equal(t, "boot", st.Name)
+	assert.Equal(t, st.Since, st.Timestamps[hexa.StateStopped])
+	assert.Contains(t, st.Timestamps, hexa.StateBooted)
+	assert.Contains(t, st.Timestamps, hexa.StateRegistered)
+}
+
+func TestStatuses_KeepFailureError(t *testing.T) {
+	r := New()
+	r.Register("broken", &svc{name: "broken", rec: &recorder{}, bootErr: errors.New("boom")})
+	require.Error(t, r.Boot())
+
+	st := r.Statuses()[0]
+	assert.Equal(t, hexa.StateFailed, st.State)
+	assert.Equal(t, "boom", st.Error)
+}